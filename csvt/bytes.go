@@ -0,0 +1,77 @@
+package csvt
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+)
+
+// BytesEncoding selects how byte slices and arrays are written inline.
+type BytesEncoding int
+
+const (
+	BYTES_BASE64 BytesEncoding = iota
+	BYTES_HEX
+)
+
+var byteType = reflect.TypeFor[byte]()
+
+// isBytes reports whether typ is a slice or array of byte. Named element
+// types, such as enums based on uint8, are written as regular arrays.
+func isBytes(typ reflect.Type) bool {
+	kind := typ.Kind()
+	return (kind == reflect.Slice || kind == reflect.Array) && typ.Elem() == byteType
+}
+
+// serializeBytes writes []byte and [N]byte values as a single string cell
+// instead of a row of integers. Since the cell belongs to the owning row,
+// equal blobs are still shared in compact mode along with their owners.
+func (s *csvtSerializer) serializeBytes(value reflect.Value) (string, bool) {
+	if !isBytes(value.Type()) {
+		return "", false
+	}
+
+	data := make([]byte, value.Len())
+	reflect.Copy(reflect.ValueOf(data), value)
+
+	if s.opts.BytesEncoding == BYTES_HEX {
+		return sprintf("%v", hex.EncodeToString(data)), true
+	}
+	return sprintf("%v", base64.StdEncoding.EncodeToString(data)), true
+}
+
+// makeBytes reads the cells written by serializeBytes. Referenced cells are
+// not handled, so blobs written as rows of integers can still be decoded.
+func (d *csvtDeserializer) makeBytes(typ reflect.Type, node *node, path string) (reflect.Value, bool, error) {
+	if node.index != -1 || !isBytes(typ) {
+		return reflect.Value{}, false, nil
+	}
+
+	text, ok := node.value.(string)
+	if !ok {
+		err := TypeMismatchf(typ.String(), node.typeName(), "%s", path).at(path)
+		return reflect.Value{}, true, err
+	}
+
+	var data []byte
+	var err error
+	if d.opts.BytesEncoding == BYTES_HEX {
+		data, err = hex.DecodeString(text)
+	} else {
+		data, err = base64.StdEncoding.DecodeString(text)
+	}
+	if err != nil {
+		return reflect.Value{}, true, InvalidValue(path, err)
+	}
+
+	if typ.Kind() == reflect.Array {
+		if len(data) != typ.Len() {
+			return reflect.Value{}, true, LengthMismatch(typ.Len(), len(data), path)
+		}
+		array := reflect.New(typ).Elem()
+		reflect.Copy(array, reflect.ValueOf(data))
+		return array, true, nil
+	}
+
+	return reflect.ValueOf(data).Convert(typ), true, nil
+}
@@ -11,6 +11,7 @@ const (
 	STR_CLOSING rune = ':'
 	PTR_HEADER rune = '$'
 	PTR_SEPARATOR rune = '_'
+	PTR_NIL rune = '~'
 	TBL_HEAD_BASE rune = '/'
 	TBL_HEAD_ROOT rune = '*'
 	TBL_INDEX_HEAD rune = 'H'
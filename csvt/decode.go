@@ -2,30 +2,73 @@ package csvt
 
 import (
 	"errors"
-	"fmt"
 	"reflect"
+	"slices"
 )
 
 // UnmarshalOptions defines the configuration for the CSV deserialization process.
 // Currently it includes:
-//   - strict: when set to true, deserialization will return an error if a field
-//             in the target struct does not exist in the CSV tables.
+//   - RequireAllFields: when set to true, deserialization will return an
+//                       ErrorMissingField if a field in the target struct
+//                       does not exist in the CSV tables.
+//   - DisallowUnknownFields: when set to true, deserialization will return an
+//                            ErrorUnknownField if a table has a column that
+//                            does not match any field of the target struct.
+//   - Strict: deprecated alias of RequireAllFields.
+//   - TimeLayout: layout used to read time.Time values, RFC 3339 with
+//                 nanosecond precision when empty.
+//   - BytesEncoding: encoding of the inline []byte and [N]byte cells, which
+//                    must match the one used to write them.
+//   - OnRowError: what to do when a root row fails to decode into a slice.
+//                 ROW_ERROR_ABORT (default) stops at the first failure,
+//                 ROW_ERROR_SKIP leaves the row out and ROW_ERROR_ZERO
+//                 appends its zero value. Both keep decoding and return
+//                 every failure joined, each wrapped in an ErrorInvalidRow,
+//                 along with the SyntaxError of every malformed row that no
+//                 root row references.
 type UnmarshalOptions struct {
-	Strict bool
+	RequireAllFields      bool
+	DisallowUnknownFields bool
+	// Deprecated: use RequireAllFields.
+	Strict        bool
+	TimeLayout    string
+	BytesEncoding BytesEncoding
+	OnRowError    RowErrorPolicy
 }
 
+// RowErrorPolicy selects how failing root rows are handled when decoding
+// into a slice.
+type RowErrorPolicy int
+
+const (
+	ROW_ERROR_ABORT RowErrorPolicy = iota
+	ROW_ERROR_SKIP
+	ROW_ERROR_ZERO
+)
+
 var defaultUnmarshalOpts = UnmarshalOptions{
-	Strict: false,
+	RequireAllFields:      false,
+	DisallowUnknownFields: false,
+	TimeLayout:            DEFAULT_TIME_LAYOUT,
+	BytesEncoding:         BYTES_BASE64,
+	OnRowError:            ROW_ERROR_ABORT,
 }
 
+var (
+	anyType      = reflect.TypeFor[any]()
+	documentType = reflect.TypeFor[map[string]any]()
+)
+
 type csvtDeserializer struct {
-	opts   UnmarshalOptions
-	tables table
+	opts     UnmarshalOptions
+	tables   table
+	pointers map[string]reflect.Value
 }
 
 // Unmarshal decodes the CSVT data into the provided value using default
 // deserialization options. The value parameter must be a pointer to a struct
-// or a slice of structs.
+// or a slice of structs. Decoding into "any" or "map[string]any" (or slices of
+// them) produces generic documents keyed by the table headers.
 //
 // Parameters:
 //   - data: the CSV-formatted input as a byte slice
@@ -56,7 +99,7 @@ func Unmarshal[T any](data []byte, value *T) error {
 //
 // Example:
 //   var result MyStruct
-//   opts := csvt.UnmarshalOptions{ Strict: true }
+//   opts := csvt.UnmarshalOptions{ RequireAllFields: true }
 //   err := csvt.UnmarshalOpts(data, &result, opts)
 func UnmarshalOpts[T any](data []byte, value *T, opts UnmarshalOptions) error {
 	tables, err := newReader().read(data)
@@ -64,15 +107,23 @@ func UnmarshalOpts[T any](data []byte, value *T, opts UnmarshalOptions) error {
 		return err
 	}
 
-	instance := &csvtDeserializer{
-		opts:   opts,
-		tables: *tables,
+	rv := reflect.ValueOf(value).Elem()
+	collect := rv.Kind() == reflect.Slice && opts.OnRowError != ROW_ERROR_ABORT
+
+	// Rows are parsed up front unless errors are collected, in which case
+	// malformed rows are reported with the root rows that reference them,
+	// and the ones no root row reaches are reported on their own.
+	if !collect {
+		err = tables.parse()
+		if err != nil {
+			return err
+		}
 	}
 
-	rv := reflect.ValueOf(value).Elem()
+	instance := newDeserializer(opts, tables)
+
 	if rv.Kind() != reflect.Slice {
-		_, err := instance.deserialize(value, 0)
-		return err
+		return instance.deserialize(rv, 0)
 	}
 
 	elemType := rv.Type().Elem()
@@ -82,112 +133,194 @@ func UnmarshalOpts[T any](data []byte, value *T, opts UnmarshalOptions) error {
 		return errors.New("root struct is not defined")
 	}
 
-	for i := 0; i < root.nodes.Size(); i++ {
-		itemPtr := reflect.New(elemType)
-		_, err := instance.deserialize(itemPtr.Interface(), i)
+	failures := []error{}
+	for i := 0; i < root.size(); i++ {
+		item := reflect.New(elemType).Elem()
+		err := instance.deserialize(item, i)
 		if err != nil {
-			return err
+			if !collect {
+				return err
+			}
+
+			failures = append(failures, InvalidRow(i, err))
+			if opts.OnRowError == ROW_ERROR_SKIP {
+				continue
+			}
+			item = reflect.New(elemType).Elem()
 		}
 
-		rv.Set(reflect.Append(rv, itemPtr.Elem()))
+		rv.Set(reflect.Append(rv, item))
 	}
 
-	return nil
+	if collect {
+		failures = append(failures, unreported(instance.tables.malformed(), failures)...)
+	}
+
+	return errors.Join(failures...)
 }
 
-func (d *csvtDeserializer) deserialize(value any, index int) (any, error) {
-	valPtr := reflect.ValueOf(value)
+// unreported filters out the syntax errors already carried by a failing
+// root row.
+func unreported(malformed []*SyntaxError, failures []error) []error {
+	reported := map[string]bool{}
+	for _, failure := range failures {
+		if syntax := IsSyntaxError(failure); syntax != nil {
+			reported[fromPointer(syntax.Table, syntax.Row).reference()] = true
+		}
+	}
 
-	if valPtr.Kind() != reflect.Ptr || valPtr.Elem().Kind() != reflect.Struct {
-		return nil, errors.New("root struct must be a pointer")
+	errs := []error{}
+	for _, syntax := range malformed {
+		if !reported[fromPointer(syntax.Table, syntax.Row).reference()] {
+			errs = append(errs, syntax)
+		}
 	}
+	return errs
+}
+
+func newDeserializer(opts UnmarshalOptions, tables *table) *csvtDeserializer {
+	return &csvtDeserializer{
+		opts:     opts,
+		tables:   *tables,
+		pointers: make(map[string]reflect.Value),
+	}
+}
 
+func (d *csvtDeserializer) deserialize(target reflect.Value, index int) (err error) {
 	root, ok := d.tables.root()
 	if !ok {
-		return nil, errors.New("root struct is not defined")
+		return errors.New("root struct is not defined")
 	}
 
-	group, ok := root.get(index)
+	group, ok, err := root.get(index)
+	if err != nil {
+		return err
+	}
 	if !ok {
-		return nil, errors.New("index does not exists")
+		return errors.New("index does not exists")
+	}
+
+	identity := fromPointer(root.key, index).reference()
+	path := rootPath(root.key, index)
+
+	structure := target
+	for structure.Kind() == reflect.Pointer {
+		if structure.IsNil() {
+			if pointer, ok := d.pointers[identity]; ok && pointer.Type() == structure.Type() {
+				structure.Set(pointer)
+				return nil
+			}
+			structure.Set(reflect.New(structure.Type().Elem()))
+		}
+		if structure.Elem().Kind() == reflect.Struct {
+			d.pointers[identity] = structure
+			defer d.forget(identity, &err)
+		}
+		structure = structure.Elem()
+	}
+
+	if structure.Kind() == reflect.Interface || structure.Kind() == reflect.Map {
+		node := fromPointer(root.key, index)
+		value, err := d.makeValue(structure.Type(), &node, path)
+		if err != nil {
+			return err
+		}
+
+		structure.Set(value)
+		return nil
+	}
+
+	if structure.Kind() != reflect.Struct {
+		return errors.New("root must be a struct, a pointer to a struct or a document")
 	}
 
-	result, err := d.makeElement(value, group)
+	err = checkSupported(structure.Type(), path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return result.Interface(), nil
+	_, err = d.makeElement(structure.Addr().Interface(), group, path)
+	return err
+}
+
+// forget drops a cached pointer whose row failed to decode, so the partially
+// decoded value is not shared with the rows decoded after it.
+func (d *csvtDeserializer) forget(identity string, err *error) {
+	if *err != nil {
+		delete(d.pointers, identity)
+	}
 }
 
-func (d *csvtDeserializer) makeElement(template any, root *group) (reflect.Value, error) {
+func (d *csvtDeserializer) makeElement(template any, root *group, path string) (reflect.Value, error) {
 	element := reflect.ValueOf(template)
 	switch element.Kind() {
 	case reflect.Struct, reflect.Ptr:
-		return d.makeStr(template, root)
+		return d.makeStr(template, root, path)
 	case reflect.Map:
-		return d.makeMap(template, root)
+		return d.makeMap(template, root, path)
 	case reflect.Slice, reflect.Array:
-		return d.makeArr(template, root)
+		return d.makeArr(template, root, path)
 	default:
-		return makeObj(template, root)
+		return makeObj(template, root, path)
 	}
 }
 
-func (d *csvtDeserializer) makeStr(template any, root *group) (reflect.Value, error) {
+func (d *csvtDeserializer) makeStr(template any, root *group, path string) (reflect.Value, error) {
 	structure := fixStr(template)
+	fields := structFields(structure.Type())
 
-	for i := 0; i < structure.NumField(); i++ {
-		name := structure.Type().Field(i).Name
-		field := structure.FieldByName(name)
-		fieldTemplate := field.Interface()
-
-		node, ok := root.findField(name)
-		if !ok {
-			if d.opts.Strict {
-				return reflect.Value{}, MissingField(name)
-			}
-			continue
+	if d.opts.DisallowUnknownFields && root.category == STR {
+		err := checkUnknownFields(fields, root, path)
+		if err != nil {
+			return reflect.Value{}, err
 		}
+	}
 
-		if !isCommonType(fieldTemplate) {
-			reference, ok := d.tables.Find(node)
-			if !ok {
-				return reflect.Value{}, fmt.Errorf("field \"%s\" reference \"%s\" not found", name, node.key())
-			}
+	for _, f := range fields {
+		name := f.name
+		location := fieldPath(path, name)
 
-			element, err := d.makeElement(fieldTemplate, reference)
-			if err != nil {
-				return reflect.Value{}, err
+		node, ok := root.findField(f.names()...)
+		if !ok {
+			if d.opts.RequireAllFields || d.opts.Strict {
+				return reflect.Value{}, MissingField(name).at(location)
 			}
-			
-			field.Set(element)
-
 			continue
 		}
 
+		field := fieldByIndex(structure, f.index)
+
 		if !field.IsValid() {
-			return reflect.Value{}, fmt.Errorf("field \"%s\" is not valid", name)
+			return reflect.Value{}, InvalidValuef(location, "field is not valid")
 		}
 		if !field.CanSet() {
-			return reflect.Value{}, fmt.Errorf("field \"%s\" cannot set", name)
+			return reflect.Value{}, InvalidValuef(location, "field cannot be set")
 		}
 
-		valueRef := reflect.ValueOf(node.value)
-		if field.Type() != valueRef.Type() {
-			if !valueRef.Type().ConvertibleTo(field.Type()) {
-				err := TypeMismatchf(field.Type().Name(), valueRef.Type().Name(), "field \"%s\"", name)
-				return reflect.Value{}, err
-			}
-
-			valueRef = valueRef.Convert(field.Type())
+		value, err := d.makeValue(field.Type(), node, location)
+		if err != nil {
+			return reflect.Value{}, err
 		}
 
-		field.Set(valueRef)
+		field.Set(value)
 	}
 	return structure, nil
 }
 
+// checkUnknownFields reports the first column of the row that matches
+// neither the name nor the aliases of any field of the target struct.
+func checkUnknownFields(fields []field, root *group, path string) error {
+	for _, header := range root.headers.Collect() {
+		known := slices.ContainsFunc(fields, func(f field) bool {
+			return slices.Contains(f.names(), header)
+		})
+		if !known {
+			return UnknownField(root.table, header, fieldPath(path, header))
+		}
+	}
+	return nil
+}
+
 func fixStr(value any) reflect.Value {
 	element := reflect.ValueOf(value)
 	if element.Kind() != reflect.Ptr {
@@ -198,100 +331,287 @@ func fixStr(value any) reflect.Value {
 	return element.Elem()
 }
 
-func (d *csvtDeserializer) makeMap(template any, root *group) (reflect.Value, error) {
+func (d *csvtDeserializer) makeMap(template any, root *group, path string) (reflect.Value, error) {
 	mapType := reflect.TypeOf(template)
-	mapElement := reflect.New(mapType).Elem()
-	mapKeysType := reflect.TypeOf(mapElement.Interface()).Key()
-	mapValuesType := reflect.TypeOf(mapElement.Interface()).Elem()
-	mapValuesElement := reflect.New(mapValuesType).Elem()
+	mapKeysType := mapType.Key()
+	mapValuesType := mapType.Elem()
 
 	mapp := reflect.MakeMap(mapType)
 
-	for _, p := range root.findFields() {
-		k := p.Key()
-		v := p.Value()
-
-		kv := reflect.ValueOf(k)
+	for _, e := range root.findEntries() {
+		location := entryPath(path, e.key)
 
-		if v.index == -1 {
-			vv := reflect.ValueOf(v.value)
-			mapp.SetMapIndex(kv.Convert(mapKeysType), vv.Convert(mapValuesType))
-			continue
-		}
-
-		reference, ok := d.tables.Find(&v)
-		if !ok {
-			return reflect.Value{}, fmt.Errorf("field \"%s\" is not valid", k)
+		key, err := d.makeKey(mapKeysType, &e.key, location)
+		if err != nil {
+			return reflect.Value{}, err
 		}
 
-		value, err := d.makeElement(mapValuesElement.Interface(), reference)
+		value, err := d.makeValue(mapValuesType, &e.value, location)
 		if err != nil {
 			return reflect.Value{}, err
 		}
 
-		mapp.SetMapIndex(kv.Convert(mapKeysType), value)
+		mapp.SetMapIndex(key, value)
 	}
 	return mapp, nil
 }
 
-func (d *csvtDeserializer) makeArr(template any, root *group) (reflect.Value, error) {
+// makeKey decodes a map key like any other cell. Inline keys are also read
+// as their text into string keys, since every key used to be written and
+// read as a string.
+func (d *csvtDeserializer) makeKey(typ reflect.Type, node *node, path string) (reflect.Value, error) {
+	if _, ok := node.value.(string); !ok && node.index == -1 && !node.isNil() && typ.Kind() == reflect.String {
+		return reflect.ValueOf(node.text()).Convert(typ), nil
+	}
+	return d.makeValue(typ, node, path)
+}
+
+func (d *csvtDeserializer) makeArr(template any, root *group, path string) (reflect.Value, error) {
 	arrType := reflect.TypeOf(template)
-	arrElement := reflect.New(arrType).Elem()
-	arrValuesType := reflect.TypeOf(arrElement.Interface()).Elem()
-	arrValuesElement := reflect.New(arrValuesType).Elem()
+	arrValuesType := arrType.Elem()
 
 	fields := root.findFields()
 	len := len(fields)
 
-	arr := reflect.MakeSlice(arrType, len, len)
+	var arr reflect.Value
+	if arrType.Kind() == reflect.Array {
+		if len != arrType.Len() {
+			return reflect.Value{}, LengthMismatch(arrType.Len(), len, path)
+		}
+		arr = reflect.New(arrType).Elem()
+	} else {
+		arr = reflect.MakeSlice(arrType, len, len)
+	}
 
 	for i, p := range fields {
 		v := p.Value()
-		if v.index == -1 {
-			elem := reflect.ValueOf(v.value)
-			if elem.Type() != arrValuesType && !elem.CanConvert(arrValuesType) {
-				err := TypeMismatchf(elem.Type().Name(), arrValuesType, "array position \"%d\"", i)
-				return reflect.Value{}, err
-			}
 
-			arr.Index(i).Set(elem.Convert(arrValuesType))
+		value, err := d.makeValue(arrValuesType, &v, indexPath(path, i))
+		if err != nil {
+			return reflect.Value{}, err
+		}
 
-			continue
+		arr.Index(i).Set(value)
+	}
+	return arr, nil
+}
+
+// makeValue builds a value of the given type from a single cell, following
+// the reference into its table when the cell is not an inline value.
+func (d *csvtDeserializer) makeValue(typ reflect.Type, node *node, path string) (reflect.Value, error) {
+	if node.isNil() {
+		return reflect.Zero(typ), nil
+	}
+
+	if value, ok, err := d.makeTime(typ, node, path); ok {
+		return value, err
+	}
+
+	if value, ok, err := makeEnum(typ, node, path); ok {
+		return value, err
+	}
+
+	if value, ok, err := unmarshalText(typ, node, path); ok {
+		return value, err
+	}
+
+	if value, ok, err := d.makeBytes(typ, node, path); ok {
+		return value, err
+	}
+
+	if typ.Kind() == reflect.Interface {
+		return d.makeInterface(typ, node, path)
+	}
+
+	err := checkSupported(typ, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		return d.makePtr(typ, node, path)
+	}
+
+	if node.index == -1 {
+		return makeScalar(typ, node, path)
+	}
+
+	reference, err := d.findReference(node, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return d.makeElement(reflect.Zero(typ).Interface(), reference, path)
+}
+
+func (d *csvtDeserializer) findReference(node *node, path string) (*group, error) {
+	reference, ok, err := d.tables.Find(node)
+	if syntax := IsSyntaxError(err); syntax != nil && syntax.Path == "" {
+		syntax.Path = path
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, MissingReference(node.reference(), path)
+	}
+	return reference, nil
+}
+
+// makePtr allocates the pointed value. Pointers to structs are cached by the
+// row they reference, so shared and cyclic references decode to the same
+// pointer; the pointer is cached before its row is decoded to break cycles.
+func (d *csvtDeserializer) makePtr(typ reflect.Type, node *node, path string) (reflect.Value, error) {
+	if node.index != -1 && typ.Elem().Kind() == reflect.Struct {
+		identity := node.reference()
+		if pointer, ok := d.pointers[identity]; ok && pointer.Type() == typ {
+			return pointer, nil
 		}
 
-		reference, ok := d.tables.Find(&v)
-		if !ok {
-			return reflect.Value{}, fmt.Errorf("array position \"%d\" reference \"%s\" not found", i, v.key())
+		reference, err := d.findReference(node, path)
+		if err != nil {
+			return reflect.Value{}, err
 		}
 
-		value, err := d.makeElement(arrValuesElement.Interface(), reference)
+		pointer := reflect.New(typ.Elem())
+		d.pointers[identity] = pointer
+
+		_, err = d.makeElement(pointer.Interface(), reference, path)
 		if err != nil {
+			delete(d.pointers, identity)
 			return reflect.Value{}, err
 		}
 
-		if value.Type() != arrValuesType && !value.CanConvert(arrValuesType) {
-			err := TypeMismatchf(value.Type().Name(), arrValuesType, "array position \"%d\"", i)
+		return pointer, nil
+	}
+
+	value, err := d.makeValue(typ.Elem(), node, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	pointer := reflect.New(typ.Elem())
+	pointer.Elem().Set(value)
+
+	return pointer, nil
+}
+
+// makeInterface resolves the concrete type of an interface-typed cell. Inline
+// values keep the type they were parsed with, while references are decoded
+// into the type registered for their table. Empty interfaces decode the
+// references to unregistered tables as generic documents.
+func (d *csvtDeserializer) makeInterface(typ reflect.Type, node *node, path string) (reflect.Value, error) {
+	var concrete reflect.Type
+	if node.index == -1 {
+		concrete = reflect.TypeOf(node.scalar())
+	} else if registered, ok := registeredType(node.key()); ok {
+		concrete = registered
+	} else if typ.NumMethod() == 0 {
+		return d.makeDocument(node, path)
+	} else {
+		return reflect.Value{}, InvalidValuef(path, "type of table \"%s\" is not registered", node.key())
+	}
+
+	if !concrete.Implements(typ) {
+		if !reflect.PointerTo(concrete).Implements(typ) {
+			return reflect.Value{}, TypeMismatchf(typ.String(), concrete.String(), "%s", path).at(path)
+		}
+		concrete = reflect.PointerTo(concrete)
+	}
+
+	value, err := d.makeValue(concrete, node, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	result := reflect.New(typ).Elem()
+	result.Set(value)
+
+	return result, nil
+}
+
+// makeDocument decodes a referenced row without a target type, the way
+// encoding/json decodes into "any": structures and maps become
+// map[string]any keyed by their headers and keys, arrays become []any and
+// inline values keep their parsed type. Structure rows are cached like
+// pointers, so cyclic graphs terminate and shared rows share their map.
+func (d *csvtDeserializer) makeDocument(node *node, path string) (reflect.Value, error) {
+	identity := node.reference()
+	if document, ok := d.pointers[identity]; ok && document.Type() == documentType {
+		return document, nil
+	}
+
+	reference, err := d.findReference(node, path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	switch reference.category {
+	case ARR:
+		return d.makeArr([]any{}, reference, path)
+	case OBJ:
+		value, ok := reference.findValue()
+		if !ok {
+			return reflect.Value{}, InvalidValuef(path, "reference \"%s\" has no value", identity)
+		}
+		return reflect.ValueOf(value.scalar()), nil
+	}
+
+	document := reflect.MakeMap(documentType)
+	if reference.category == STR {
+		d.pointers[identity] = document
+	}
+
+	for _, p := range reference.findFields() {
+		v := p.Value()
+
+		value, err := d.makeValue(anyType, &v, keyPath(path, reflect.ValueOf(p.Key())))
+		if err != nil {
+			delete(d.pointers, identity)
 			return reflect.Value{}, err
 		}
 
-		arr.Index(i).Set(value.Convert(arrValuesType))
+		document.SetMapIndex(reflect.ValueOf(p.Key()), value)
 	}
-	return arr, nil
+
+	return document, nil
+}
+
+func makeScalar(typ reflect.Type, node *node, path string) (reflect.Value, error) {
+	if number, ok := node.value.(number); ok && isNumeric(typ) {
+		return number.convert(typ, path)
+	}
+
+	valueRef := reflect.ValueOf(node.scalar())
+	if typ != valueRef.Type() {
+		if !isConvertible(valueRef.Type(), typ) {
+			return reflect.Value{}, TypeMismatchf(typ.Name(), valueRef.Type().Name(), "%s", path).at(path)
+		}
+
+		valueRef = valueRef.Convert(typ)
+	}
+
+	return valueRef, nil
 }
 
-func makeObj(template any, root *group) (reflect.Value, error) {
+func makeObj(template any, root *group, path string) (reflect.Value, error) {
 	element := reflect.ValueOf(template)
 
 	node, ok := root.findValue()
 	if !ok {
-		return reflect.Value{}, fmt.Errorf("field category \"%s\" not found", root.category)
+		return reflect.Value{}, InvalidValuef(path, "field category \"%s\" not found", root.category)
 	}
 
-	valueRef := reflect.ValueOf(node.value)
-	if element.Type().Kind() != valueRef.Type().Kind() {
-		err := TypeMismatchf(element.Type().Name(), valueRef.Type().Name(), "field category \"%s\" type", root.category)
-		return reflect.Value{}, err
-	}
+	return makeScalar(element.Type(), node, path)
+}
 
-	return valueRef.Convert(element.Type()), nil
+// isConvertible restricts reflect conversions to values of the same family,
+// so named types are decoded from their base type while numbers are never
+// turned into strings or the other way around.
+func isConvertible(from, to reflect.Type) bool {
+	if from.Kind() == reflect.String || to.Kind() == reflect.String ||
+		from.Kind() == reflect.Bool || to.Kind() == reflect.Bool {
+		return from.Kind() == to.Kind()
+	}
+	return isCommonType(from) && isCommonType(to)
 }
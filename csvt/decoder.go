@@ -0,0 +1,126 @@
+package csvt
+
+import (
+	"io"
+	"iter"
+)
+
+// Decoder reads the root rows of a CSVT document from an input stream one at
+// a time. Rows are parsed when they are requested and references are
+// resolved lazily from the secondary tables, so no row is materialized
+// before it is needed and iteration can stop early.
+//
+// Since secondary tables may follow the root table, the whole input is
+// buffered as raw text on the first call to Next. The text of each root row
+// is released once it has been decoded, unless the root table is referenced
+// by other rows, while secondary rows are kept once parsed, as later rows may
+// reference them.
+//
+// Pointer identity is kept within a row, but not across rows.
+type Decoder struct {
+	source   io.Reader
+	opts     UnmarshalOptions
+	document *Document
+	release  bool
+	index    int
+	err      error
+}
+
+// NewDecoder returns a decoder that reads from r using default
+// deserialization options.
+//
+// Example:
+//   decoder := csvt.NewDecoder(file)
+//   for {
+//     var item MyStruct
+//     err := decoder.Next(&item)
+//     if err == io.EOF {
+//       break
+//     }
+//     if err != nil {
+//       return err
+//     }
+//   }
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderOpts(r, defaultUnmarshalOpts)
+}
+
+// NewDecoderOpts returns a decoder that reads from r using the given
+// deserialization options.
+func NewDecoderOpts(r io.Reader, opts UnmarshalOptions) *Decoder {
+	return &Decoder{
+		source: r,
+		opts:   opts,
+	}
+}
+
+// Next decodes the next root row into the provided value, which must be a
+// pointer to a struct, a pointer to a pointer to a struct or a pointer to a
+// document. It returns io.EOF once every root row has been decoded.
+func (d *Decoder) Next(value any) error {
+	document, err := d.parse()
+	if err != nil {
+		return err
+	}
+
+	if d.index >= document.Len() {
+		return io.EOF
+	}
+
+	index := d.index
+	d.index++
+
+	err = document.DecodeRow(index, value)
+	if d.release {
+		document.release(index)
+	}
+
+	return err
+}
+
+func (d *Decoder) parse() (*Document, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if d.document == nil {
+		document, err := parseDocument(d.source, d.opts)
+		if err != nil {
+			d.err = err
+			return nil, err
+		}
+		d.document = document
+		d.release = document.releasable()
+	}
+
+	return d.document, nil
+}
+
+// Rows returns an iterator over the remaining root rows of the decoder. The
+// iteration stops after the first error, which is yielded with a zero value.
+//
+// Example:
+//   for item, err := range csvt.Rows[MyStruct](decoder) {
+//     if err != nil {
+//       return err
+//     }
+//   }
+func Rows[T any](decoder *Decoder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var item T
+			err := decoder.Next(&item)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package csvt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Document is a parsed CSVT document that can be queried repeatedly without
+// being parsed again. Rows are only parsed when they are decoded, so single
+// rows can be read without materializing the rest of the document.
+//
+// A Document is not safe for concurrent use.
+type Document struct {
+	opts   UnmarshalOptions
+	tables *table
+	root   *nexus
+}
+
+// Parse reads the CSVT data into a Document using default deserialization
+// options.
+//
+// Example:
+//   document, err := csvt.Parse(data)
+//   if err != nil {
+//     return err
+//   }
+//
+//   var last MyStruct
+//   err = document.DecodeRow(document.Len()-1, &last)
+func Parse(data []byte) (*Document, error) {
+	return ParseOpts(data, defaultUnmarshalOpts)
+}
+
+// ParseOpts reads the CSVT data into a Document using the given
+// deserialization options, which apply to every decoded row.
+func ParseOpts(data []byte, opts UnmarshalOptions) (*Document, error) {
+	return parseDocument(bytes.NewReader(data), opts)
+}
+
+func parseDocument(source io.Reader, opts UnmarshalOptions) (*Document, error) {
+	tables, err := newReader().readFrom(source)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := tables.root()
+	if !ok {
+		return nil, errors.New("root struct is not defined")
+	}
+
+	return &Document{
+		opts:   opts,
+		tables: tables,
+		root:   root,
+	}, nil
+}
+
+// Len returns the number of rows of the root table.
+func (d *Document) Len() int {
+	return d.root.size()
+}
+
+// DecodeRow decodes the root row at the given index into the provided value,
+// which must be a pointer to a struct, a pointer to a pointer to a struct or
+// a pointer to a document. Pointer identity is kept within the row, but not
+// across calls.
+func (d *Document) DecodeRow(index int, value any) error {
+	target := reflect.ValueOf(value)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return errors.New("value must be a non-nil pointer")
+	}
+
+	if index < 0 || index >= d.Len() {
+		return fmt.Errorf("row %d is out of range, the document has %d rows", index, d.Len())
+	}
+
+	return newDeserializer(d.opts, d.tables).deserialize(target.Elem(), index)
+}
+
+// releasable reports whether the root rows can be dropped once decoded. It
+// is not the case when any row references the root table, since nested
+// values of the root type, such as the nodes of a tree, are written there.
+func (d *Document) releasable() bool {
+	token := fmt.Sprintf("%c%s%c", PTR_HEADER, d.root.key, PTR_SEPARATOR)
+	for _, key := range d.tables.nexus.Keys() {
+		nexus, _ := d.tables.nexus.Get(key)
+		for _, row := range nexus.rows {
+			if strings.Contains(row, token) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// release drops the raw text of a root row that will not be decoded again.
+func (d *Document) release(index int) {
+	d.root.rows[index] = ""
+}
@@ -1,6 +1,8 @@
 package csvt
 
 import (
+	"bufio"
+	"bytes"
 	"crypto/sha1"
 	"encoding/hex"
 	"errors"
@@ -8,38 +10,69 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
-
-	"github.com/Rafael24595/go-collections/collection"
 )
 
 const (
 	HEADER_ROOT       = string(TBL_HEAD_BASE) + string(TBL_HEAD_ROOT) + string(TBL_HEAD_ROOT)
 	HEADER_REGULAR    = string(TBL_HEAD_BASE) + string(TBL_HEAD_BASE) + string(TBL_HEAD_BASE)
 	POINTER_INDEX_FIX = 2
+	COMMON_MAP        = "common-map"
+	COMMON_ARRAY      = "common-array"
+	ANONYMOUS_STRUCT  = "struct"
 )
 
 // MarshalOptions defines the configuration for the CSV serialization process.
 // Currently it includes:
 //   - Compact: when set to true, dentical serialized rows should be
 //              deduplicated by caching and referenced via pointers.
+//   - TimeLayout: layout used to write time.Time values, RFC 3339 with
+//                 nanosecond precision when empty.
+//   - DurationString: when set to true, time.Duration values are written as
+//                     Go duration strings ("1h30m0s") instead of nanoseconds.
+//   - MapKeyOrder: comparator used to sort map entries, returning a negative
+//                  number when a sorts before b. When nil, keys are sorted by
+//                  their natural order.
+//   - TableOrder: order of the tables written after the root table, either
+//                 TABLE_ORDER_FIRST_SEEN (default) or TABLE_ORDER_ALPHABETICAL.
+//   - BytesEncoding: encoding of the inline []byte and [N]byte cells, either
+//                    BYTES_BASE64 (default) or BYTES_HEX.
 type MarshalOptions struct {
-	Compact bool
+	Compact        bool
+	TimeLayout     string
+	DurationString bool
+	MapKeyOrder    func(a, b any) int
+	TableOrder     TableOrder
+	BytesEncoding  BytesEncoding
 }
 
 var defaultMarshalOpts = MarshalOptions{
-	Compact: true,
+	Compact:       true,
+	TimeLayout:    DEFAULT_TIME_LAYOUT,
+	TableOrder:    TABLE_ORDER_FIRST_SEEN,
+	BytesEncoding: BYTES_BASE64,
 }
 
 type csvtSerializer struct {
 	opts        MarshalOptions
+	rootKey     string
+	roots       int
+	order       []string
 	tables      map[string][]string
 	cache       map[string]string
 	nilPointers map[string]string
+	pointers    map[pointerKey]string
+}
+
+// pointerKey identifies an addressed value by its pointer type and address,
+// so two fields sharing a pointer serialize to the same row.
+type pointerKey struct {
+	typ     reflect.Type
+	address uintptr
 }
 
 // Marshal encodes the provided value into CSVT using default
 // serialization options. The value parameter must be a struct
-// or a slice of structs.
+// or a slice of structs, optionally passed through pointers.
 //
 // Parameters:
 //   - value: a struct or slice of structs to serialize
@@ -71,101 +104,122 @@ func Marshal(v ...any) ([]byte, error) {
 //   opts := csvt.MarshalOptions{ Compact: false }
 //   bytes, err := csvt.MarshalOpts(opts, item)
 func MarshalOpts(opts MarshalOptions, v ...any) ([]byte, error) {
-	instance := &csvtSerializer{
+	if len(v) == 0 {
+		return make([]byte, 0), nil
+	}
+
+	buffer := &bytes.Buffer{}
+
+	encoder := NewEncoder(buffer, opts)
+	if err := encoder.Encode(v...); err != nil {
+		return make([]byte, 0), err
+	}
+	if err := encoder.Close(); err != nil {
+		return make([]byte, 0), err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func newSerializer(opts MarshalOptions) *csvtSerializer {
+	return &csvtSerializer{
 		opts:        opts,
+		order:       []string{},
 		tables:      make(map[string][]string),
 		cache:       make(map[string]string),
 		nilPointers: make(map[string]string),
+		pointers:    make(map[pointerKey]string),
 	}
+}
 
-	if len(v) == 0 {
-		return make([]byte, 0), nil
-	}
+// serializeRoots writes the given values as rows of the root table, whose
+// type is taken from the first root ever serialized.
+func (s *csvtSerializer) serializeRoots(v ...any) error {
+	for _, e := range v {
+		root, err := dereference(e)
+		if err != nil {
+			return err
+		}
 
-	rootKey := instance.key(reflect.ValueOf(v[0]))
-	if rootKey == "common-array" || rootKey == "common-map" {
-		return make([]byte, 0), errors.New("common structures cannot be root")
-	}
+		if s.rootKey == "" {
+			rootKey := s.key(reflect.ValueOf(root))
+			if rootKey == COMMON_ARRAY || rootKey == COMMON_MAP {
+				return errors.New("common structures cannot be root")
+			}
+			s.rootKey = rootKey
+		}
 
-	for _, e := range v {
-		if reflect.ValueOf(e).Kind() == reflect.Pointer {
-			return make([]byte, 0), errors.New("not supported yet")
+		path := indexPath(reflect.TypeOf(root).Name(), s.roots)
+		s.roots++
+
+		if entity := reflect.ValueOf(e); entity.Kind() == reflect.Pointer {
+			_, err = s.serializePointer(entity, path)
+		} else {
+			_, err = s.serialize(root, path)
 		}
-		_, err := instance.serialize(e)
 		if err != nil {
-			return make([]byte, 0), err
+			return err
 		}
 	}
 
-	return []byte(instance.formatTables(rootKey)), nil
+	return nil
 }
 
-func (s *csvtSerializer) formatTables(rootKey string) string {
-	keys := collection.DictionaryFromMap(s.tables).
-		KeysVector().
-		Sort(func(a, b string) bool {
-			return a == rootKey
-		})
-
-	buffer := ""
-	for _, k := range keys.Collect() {
+func (s *csvtSerializer) writeTables(writer *bufio.Writer) {
+	for _, k := range s.tableKeys() {
 		rows := s.tables[k]
 
 		pattern := HEADER_REGULAR
-		if k == rootKey {
+		if k == s.rootKey {
 			pattern = HEADER_ROOT
 		}
 
-		buffer += fmt.Sprintf("\n%s %s\n", pattern, k)
-		buffer += s.formatRows(rows)
+		fmt.Fprintf(writer, "\n%s %s\n", pattern, k)
+		s.writeRows(writer, rows)
 	}
-
-	return buffer
 }
 
-func (s *csvtSerializer) formatRows(rows []string) string {
-	buffer := ""
+func (s *csvtSerializer) writeRows(writer *bufio.Writer, rows []string) {
 	for i, r := range rows {
 		index := strconv.FormatInt(int64(i-1), 10)
 		if i == 0 {
 			index = string(TBL_INDEX_HEAD)
 		}
-		buffer += fmt.Sprintf("%s%s\n", s.formatIndexArrow(index), r)
+		writer.WriteString(s.formatIndexArrow(index))
+		writer.WriteString(r)
+		writer.WriteByte('\n')
 	}
-
-	return buffer
 }
 
-func (s *csvtSerializer) serialize(entity any) (string, error) {
+func (s *csvtSerializer) serialize(entity any, path string) (string, error) {
 	rEntity := reflect.ValueOf(entity)
 
-	key := s.key(rEntity)
+	err := checkSupported(rEntity.Type(), path)
+	if err != nil {
+		return "", err
+	}
 
-	if _, exists := s.tables[key]; !exists {
-		headers, _ := s.headers(entity)
-		s.tables[key] = append(s.tables[key], headers)
-		if s.canEmpty(rEntity) {
-			item, err := s.makeEmpty(rEntity)
-			if err != nil {
-				return "", err
-			}
+	key := s.key(rEntity)
 
-			s.tables[key] = append(s.tables[key], item)
-			s.nilPointers[key] = s.formatPointerReference(key, len(s.tables[key]))
-		}
+	err = s.prepareTable(key, rEntity)
+	if err != nil {
+		return "", err
 	}
 
 	if pointer, ok := s.nilPointers[key]; ok && s.isEmpty(rEntity) {
 		return pointer, nil
 	}
 
-	row, err := s.serializeEntity(entity, rEntity)
+	row, err := s.serializeEntity(entity, rEntity, path)
 	if err != nil {
 		return "", err
 	}
 
+	// Rows are shared within their table only, since values of different
+	// types may write the same row and the table names their type.
+	cached := key + "\x00" + row
 	if s.opts.Compact {
-		if pointer, ok := s.cache[row]; ok {
+		if pointer, ok := s.cache[cached]; ok {
 			return pointer, nil
 		}
 	}
@@ -174,12 +228,34 @@ func (s *csvtSerializer) serialize(entity any) (string, error) {
 	pointer := s.formatPointerReference(key, len(s.tables[key]))
 
 	if s.opts.Compact {
-		s.cache[row] = pointer
+		s.cache[cached] = pointer
 	}
 
 	return pointer, nil
 }
 
+func (s *csvtSerializer) prepareTable(key string, rEntity reflect.Value) error {
+	if _, exists := s.tables[key]; exists {
+		return nil
+	}
+
+	s.order = append(s.order, key)
+
+	headers, _ := s.headers(rEntity.Interface())
+	s.tables[key] = append(s.tables[key], headers)
+	if s.canEmpty(rEntity) {
+		item, err := s.makeEmpty(rEntity)
+		if err != nil {
+			return err
+		}
+
+		s.tables[key] = append(s.tables[key], item)
+		s.nilPointers[key] = s.formatPointerReference(key, len(s.tables[key]))
+	}
+
+	return nil
+}
+
 func (s *csvtSerializer) canEmpty(entity reflect.Value) bool {
 	kind := entity.Kind()
 	return kind == reflect.Array || kind == reflect.Chan ||
@@ -204,62 +280,53 @@ func (s *csvtSerializer) makeEmpty(entity reflect.Value) (string, error) {
 	}
 }
 
-func (s *csvtSerializer) serializeEntity(entity any, rEntity reflect.Value) (string, error) {
+func (s *csvtSerializer) serializeEntity(entity any, rEntity reflect.Value, path string) (string, error) {
 	switch rEntity.Kind() {
 	case reflect.Struct:
-		return s.serializeStruct(rEntity)
+		return s.serializeStruct(rEntity, path)
 	case reflect.Map:
-		return s.serializeMap(rEntity)
+		return s.serializeMap(rEntity, path)
 	case reflect.Slice, reflect.Array:
-		return s.serializeArray(rEntity)
+		return s.serializeArray(rEntity, path)
 	default:
 		return s.serializeObject(entity, rEntity), nil
 	}
 }
 
-func (s *csvtSerializer) serializeStruct(entity reflect.Value) (string, error) {
-	var err error
+func (s *csvtSerializer) serializeStruct(entity reflect.Value, path string) (string, error) {
 	strRow := []string{}
 
-	for i := 0; i < entity.NumField(); i++ {
-		value := entity.Field(i).Interface()
-		if !isCommonType(value) {
-			value, err = s.serialize(value)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			value = sprintf("%v", value)
+	for _, field := range structFields(entity.Type()) {
+		fieldValue, err := entity.FieldByIndexErr(field.index)
+		if err != nil || field.omitEmpty && isEmptyValue(fieldValue) {
+			strRow = append(strRow, string(PTR_NIL))
+			continue
+		}
+
+		value, err := s.serializeValue(fieldValue, fieldPath(path, field.name))
+		if err != nil {
+			return "", err
 		}
 
-		strRow = append(strRow, fmt.Sprintf("%v", value))
+		strRow = append(strRow, value)
 	}
 	return fmt.Sprintf("%v%c", strings.Join(strRow, string(STR_SEPARATOR)), STR_CLOSING), nil
 }
 
-func (s *csvtSerializer) serializeMap(entity reflect.Value) (string, error) {
-	var err error
+func (s *csvtSerializer) serializeMap(entity reflect.Value, path string) (string, error) {
 	mapRow := []string{}
 
-	for _, k := range entity.MapKeys() {
-		key := k.Interface()
-		if !isCommonType(key) {
-			key, err = s.serialize(key)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			key = sprintf("%v", key)
+	for _, k := range s.mapKeys(entity) {
+		location := keyPath(path, k)
+
+		key, err := s.serializeValue(k, location)
+		if err != nil {
+			return "", err
 		}
 
-		value := entity.MapIndex(k).Interface()
-		if !isCommonType(value) {
-			value, err = s.serialize(value)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			value = sprintf("%v", value)
+		value, err := s.serializeValue(entity.MapIndex(k), location)
+		if err != nil {
+			return "", err
 		}
 
 		mapRow = append(mapRow, fmt.Sprintf("%v%c%v", key, MAP_LINKER, value))
@@ -268,27 +335,117 @@ func (s *csvtSerializer) serializeMap(entity reflect.Value) (string, error) {
 	return fmt.Sprintf("%v%c", strings.Join(mapRow, string(MAP_SEPARATOR)), MAP_CLOSING), nil
 }
 
-func (s *csvtSerializer) serializeArray(entity reflect.Value) (string, error) {
-	var err error
+func (s *csvtSerializer) serializeArray(entity reflect.Value, path string) (string, error) {
 	arrayRow := []string{}
 
 	for i := 0; i < entity.Len(); i++ {
-		value := entity.Index(i).Interface()
-		if !isCommonType(value) {
-			value, err = s.serialize(value)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			value = sprintf("%v", value)
+		value, err := s.serializeValue(entity.Index(i), indexPath(path, i))
+		if err != nil {
+			return "", err
 		}
 
-		arrayRow = append(arrayRow, fmt.Sprintf("%v", value))
+		arrayRow = append(arrayRow, value)
 	}
 
 	return fmt.Sprintf("%v%c", strings.Join(arrayRow, string(ARR_SEPARATOR)), ARR_CLOSING), nil
 }
 
+// serializeValue renders a single cell: common types and types implementing
+// Marshaler or encoding.TextMarshaler are written inline, nil pointers and
+// interfaces as the nil marker, and everything else as a reference to the
+// row that holds it.
+func (s *csvtSerializer) serializeValue(value reflect.Value, path string) (string, error) {
+	kind := value.Kind()
+	if (kind == reflect.Interface || kind == reflect.Pointer) && value.IsNil() {
+		return string(PTR_NIL), nil
+	}
+
+	if kind == reflect.Interface {
+		return s.serializeValue(value.Elem(), path)
+	}
+
+	if cell, ok := s.serializeTime(value); ok {
+		return cell, nil
+	}
+
+	if name, ok := enumName(value); ok {
+		return sprintf("%v", name), nil
+	}
+
+	text, ok, err := marshalText(value)
+	if err != nil {
+		return "", InvalidValue(path, err)
+	}
+	if ok {
+		return sprintf("%v", text), nil
+	}
+
+	if kind == reflect.Pointer {
+		return s.serializePointer(value, path)
+	}
+
+	if cell, ok := s.serializeBytes(value); ok {
+		return cell, nil
+	}
+
+	if isCommonType(value.Type()) {
+		return formatCommon(value), nil
+	}
+
+	return s.serialize(value.Interface(), path)
+}
+
+// serializePointer writes each pointed struct once, reserving its row before
+// serializing the content so that cyclic references resolve to that same row.
+// Rows owned by a pointer are never shared through the compact cache, as that
+// would merge distinct pointers with equal content.
+func (s *csvtSerializer) serializePointer(value reflect.Value, path string) (string, error) {
+	if value.IsNil() {
+		return string(PTR_NIL), nil
+	}
+
+	entity := value.Elem()
+	if entity.Kind() != reflect.Struct {
+		return s.serializeValue(entity, path)
+	}
+
+	identity := pointerKey{
+		typ:     value.Type(),
+		address: value.Pointer(),
+	}
+
+	if pointer, ok := s.pointers[identity]; ok {
+		return pointer, nil
+	}
+
+	err := checkSupported(entity.Type(), path)
+	if err != nil {
+		return "", err
+	}
+
+	key := s.key(entity)
+
+	err = s.prepareTable(key, entity)
+	if err != nil {
+		return "", err
+	}
+
+	s.tables[key] = append(s.tables[key], "")
+	position := len(s.tables[key])
+
+	pointer := s.formatPointerReference(key, position)
+	s.pointers[identity] = pointer
+
+	row, err := s.serializeEntity(entity.Interface(), entity, path)
+	if err != nil {
+		return "", err
+	}
+
+	s.tables[key][position-1] = row
+
+	return pointer, nil
+}
+
 func (s *csvtSerializer) serializeObject(entity any, rEntity reflect.Value) string {
 	if rEntity.Kind() == reflect.String {
 		return sprintf("%s", fmt.Sprintf("%v", entity))
@@ -297,14 +454,30 @@ func (s *csvtSerializer) serializeObject(entity any, rEntity reflect.Value) stri
 }
 
 func (s *csvtSerializer) key(val reflect.Value) string {
-	switch val.Kind() {
+	return tableKey(val.Type())
+}
+
+// tableKey names the table of a type after the type and a hash of its
+// package. Generic instantiations keep the name of the generic type and hash
+// their type arguments too, and anonymous structs hash their definition, so
+// names are unique and free of the delimiters used by rows.
+func tableKey(typ reflect.Type) string {
+	switch typ.Kind() {
 	case reflect.Map:
-		return "common-map"
+		return COMMON_MAP
 	case reflect.Slice, reflect.Array:
-		return "common-array"
+		return COMMON_ARRAY
+	}
+
+	name := typ.Name()
+	switch {
+	case name == "":
+		return fmt.Sprintf("%s&%s", ANONYMOUS_STRUCT, sha1Identifier(typ.String()))
+	case strings.ContainsRune(name, '['):
+		base, _, _ := strings.Cut(name, "[")
+		return fmt.Sprintf("%s&%s", base, sha1Identifier(typ.PkgPath()+"."+name))
 	default:
-		typ := val.Type()
-		return fmt.Sprintf("%s&%s", typ.Name(), s.sha1Identifier(typ.PkgPath()))
+		return fmt.Sprintf("%s&%s", name, sha1Identifier(typ.PkgPath()))
 	}
 }
 
@@ -318,14 +491,8 @@ func (s *csvtSerializer) headers(value any) (string, bool) {
 		return "", false
 	}
 
-	for i := 0; i < val.NumField(); i++ {
-		field := typ.Field(i).Name
-		csvTag := typ.Field(i).Tag.Get("csv")
-		if csvTag != "" {
-			field = csvTag
-		}
-
-		headers = append(headers, field)
+	for _, field := range structFields(typ) {
+		headers = append(headers, field.name)
 	}
 
 	return strings.Join(headers, string(HEA_SEPARATOR)), true
@@ -339,31 +506,66 @@ func (s *csvtSerializer) formatPointerReference(key string, position int) string
 	return fmt.Sprintf("$%s_%v", key, position-POINTER_INDEX_FIX)
 }
 
-func (s csvtSerializer) sha1Identifier(input string) string {
+func sha1Identifier(input string) string {
 	hash := sha1.New()
 	hash.Write([]byte(input))
 	hashInBytes := hash.Sum(nil)
 	return hex.EncodeToString(hashInBytes)
 }
 
+func dereference(value any) (any, error) {
+	entity := reflect.ValueOf(value)
+	for entity.Kind() == reflect.Pointer && !entity.IsNil() {
+		entity = entity.Elem()
+	}
+
+	if !entity.IsValid() || entity.Kind() == reflect.Pointer {
+		return nil, errors.New("root value cannot be nil")
+	}
+
+	return entity.Interface(), nil
+}
+
 func sprintf(pattern string, values ...any) string {
 	for i, v := range values {
 		switch v := v.(type) {
 		case string:
-			fixed := strings.ReplaceAll(v, "\"", "\\'")
-			fixed = strings.ReplaceAll(fixed, "\\n", "\\\\n")
-			fixed = strings.ReplaceAll(fixed, "\n", "\\n")
-			values[i] = fmt.Sprintf("\"%v\"", fixed)
+			values[i] = quote(v)
 		}
 	}
 	return fmt.Sprintf(pattern, values...)
 }
 
-func isCommonType(value interface{}) bool {
-	switch value.(type) {
-	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+// isCommonType reports whether values of the type are written inline. Named
+// types are common when their underlying kind is, so enums such as
+// "type Status string" are written like their base type.
+func isCommonType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
 		return true
 	default:
 		return false
 	}
 }
+
+// formatCommon writes a common value from its kind rather than with "%v",
+// which would use the String method of named types.
+func formatCommon(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return sprintf("%v", value.String())
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(value.Float(), value.Type().Bits())
+	default:
+		return sprintf("%v", value.Interface())
+	}
+}
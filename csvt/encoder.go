@@ -0,0 +1,78 @@
+package csvt
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// Encoder writes CSVT documents to an output stream. Since every table is
+// written as a whole, the values given to Encode are serialized as they
+// arrive, accumulating their rows per table, and the document is written
+// through a buffered writer once the encoder is closed.
+type Encoder struct {
+	writer     *bufio.Writer
+	serializer *csvtSerializer
+	closed     bool
+	err        error
+}
+
+// NewEncoder returns an encoder that writes to w using the given
+// serialization options.
+//
+// Example:
+//   encoder := csvt.NewEncoder(file, csvt.MarshalOptions{ Compact: true })
+//   for _, item := range items {
+//     if err := encoder.Encode(item); err != nil {
+//       return err
+//     }
+//   }
+//   return encoder.Close()
+func NewEncoder(w io.Writer, opts MarshalOptions) *Encoder {
+	return &Encoder{
+		writer:     bufio.NewWriter(w),
+		serializer: newSerializer(opts),
+	}
+}
+
+// Encode serializes the given values as rows of the root table. The type of
+// the root table is taken from the first value encoded in the document.
+//
+// A failing value may leave rows of the document half written, so the first
+// error is kept: later calls to Encode and Close return it and the document
+// is never written.
+func (e *Encoder) Encode(v ...any) error {
+	if e.closed {
+		return errors.New("encoder is closed")
+	}
+	if e.err != nil {
+		return e.err
+	}
+
+	e.err = e.serializer.serializeRoots(v...)
+	return e.err
+}
+
+// Close writes the document and flushes the buffered writer. It is the only
+// point that ends the document, since every table is written as a whole.
+// Nothing is written when no value has been encoded, and the error of a
+// failed Encode is returned instead of writing the document. The underlying
+// writer is not closed.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+
+	if e.err != nil {
+		return e.err
+	}
+
+	if e.serializer.rootKey == "" {
+		return nil
+	}
+
+	e.serializer.writeTables(e.writer)
+
+	return e.writer.Flush()
+}
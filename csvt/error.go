@@ -3,6 +3,7 @@ package csvt
 import (
 	"errors"
 	"fmt"
+	"reflect"
 )
 
 func IsMissingField(err error) *ErrorMissingField {
@@ -15,6 +16,7 @@ func IsMissingField(err error) *ErrorMissingField {
 
 type ErrorMissingField struct {
 	Field string
+	Path  string
 }
 
 func MissingField(field string) *ErrorMissingField {
@@ -24,9 +26,17 @@ func MissingField(field string) *ErrorMissingField {
 }
 
 func (e *ErrorMissingField) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("missing required field: %s", e.Path)
+	}
 	return fmt.Sprintf("missing required field: %s", e.Field)
 }
 
+func (e *ErrorMissingField) at(path string) *ErrorMissingField {
+	e.Path = path
+	return e
+}
+
 func IsTypeMismatch(err error) *ErrorTypeMismatch {
 	var e *ErrorTypeMismatch
 	if errors.As(err, &e) {
@@ -51,8 +61,220 @@ type ErrorTypeMismatch struct {
 	Element  string
 	Expected any
 	Found    any
+	Path     string
 }
 
 func (e *ErrorTypeMismatch) Error() string {
 	return fmt.Sprintf("\"%s\" must be \"%v\", but \"%v\" found", e.Element, e.Expected, e.Found)
 }
+
+func (e *ErrorTypeMismatch) at(path string) *ErrorTypeMismatch {
+	e.Path = path
+	return e
+}
+
+func IsUnsupportedType(err error) *ErrorUnsupportedType {
+	var e *ErrorUnsupportedType
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+func UnsupportedType(typ reflect.Type) *ErrorUnsupportedType {
+	return &ErrorUnsupportedType{
+		Type: typ.String(),
+	}
+}
+
+type ErrorUnsupportedType struct {
+	Type string
+	Path string
+}
+
+func (e *ErrorUnsupportedType) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("%s type \"%s\" is not supported", e.Path, e.Type)
+	}
+	return fmt.Sprintf("type \"%s\" is not supported", e.Type)
+}
+
+func (e *ErrorUnsupportedType) at(path string) *ErrorUnsupportedType {
+	e.Path = path
+	return e
+}
+
+func IsUnknownField(err error) *ErrorUnknownField {
+	var e *ErrorUnknownField
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+func UnknownField(table, field, path string) *ErrorUnknownField {
+	return &ErrorUnknownField{
+		Table: table,
+		Field: field,
+		Path:  path,
+	}
+}
+
+// ErrorUnknownField reports a table column that does not match any field of
+// the target struct.
+type ErrorUnknownField struct {
+	Table string
+	Field string
+	Path  string
+}
+
+func (e *ErrorUnknownField) Error() string {
+	return fmt.Sprintf("%s unknown field \"%s\" in table \"%s\"", e.Path, e.Field, e.Table)
+}
+
+func IsLengthMismatch(err error) *ErrorLengthMismatch {
+	var e *ErrorLengthMismatch
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+func LengthMismatch(expected, found int, path string) *ErrorLengthMismatch {
+	return &ErrorLengthMismatch{
+		Expected: expected,
+		Found:    found,
+		Path:     path,
+	}
+}
+
+// ErrorLengthMismatch reports a stored row whose number of elements differs
+// from the length of the target array.
+type ErrorLengthMismatch struct {
+	Expected int
+	Found    int
+	Path     string
+}
+
+func (e *ErrorLengthMismatch) Error() string {
+	return fmt.Sprintf("%s must have %d elements, but %d found", e.Path, e.Expected, e.Found)
+}
+
+func IsInvalidValue(err error) *ErrorInvalidValue {
+	var e *ErrorInvalidValue
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+func InvalidValue(path string, err error) *ErrorInvalidValue {
+	return &ErrorInvalidValue{
+		Path: path,
+		Err:  err,
+	}
+}
+
+func InvalidValuef(path string, format string, args ...any) *ErrorInvalidValue {
+	return InvalidValue(path, fmt.Errorf(format, args...))
+}
+
+// ErrorInvalidValue reports a value that cannot be converted from or into
+// its cell, such as a malformed time, an out of range number or a failing
+// Unmarshaler, wrapping the cause.
+type ErrorInvalidValue struct {
+	Path string
+	Err  error
+}
+
+func (e *ErrorInvalidValue) Error() string {
+	return fmt.Sprintf("%s %v", e.Path, e.Err)
+}
+
+func (e *ErrorInvalidValue) Unwrap() error {
+	return e.Err
+}
+
+func IsMissingReference(err error) *ErrorMissingReference {
+	var e *ErrorMissingReference
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+func MissingReference(reference, path string) *ErrorMissingReference {
+	return &ErrorMissingReference{
+		Reference: reference,
+		Path:      path,
+	}
+}
+
+// ErrorMissingReference reports a reference to a row that does not exist.
+type ErrorMissingReference struct {
+	Reference string
+	Path      string
+}
+
+func (e *ErrorMissingReference) Error() string {
+	return fmt.Sprintf("%s reference \"%s\" not found", e.Path, e.Reference)
+}
+
+func IsInvalidRow(err error) *ErrorInvalidRow {
+	var e *ErrorInvalidRow
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+func InvalidRow(row int, err error) *ErrorInvalidRow {
+	return &ErrorInvalidRow{
+		Row: row,
+		Err: err,
+	}
+}
+
+// ErrorInvalidRow wraps the failure of a root row collected while decoding,
+// so the remaining rows could still be decoded.
+type ErrorInvalidRow struct {
+	Row int
+	Err error
+}
+
+func (e *ErrorInvalidRow) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *ErrorInvalidRow) Unwrap() error {
+	return e.Err
+}
+
+func IsSyntaxError(err error) *SyntaxError {
+	var e *SyntaxError
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+// SyntaxError locates malformed input. Row is the position of the row within
+// its table, or -1 when the error is not inside a row. Line and Column are
+// 1-based, and Snippet holds the text around the offending column. Path is
+// set when the row was parsed lazily while decoding the given value.
+type SyntaxError struct {
+	Table   string
+	Row     int
+	Line    int
+	Column  int
+	Snippet string
+	Message string
+	Path    string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Table == "" {
+		return fmt.Sprintf("syntax error at line %d, column %d: %s: %s", e.Line, e.Column, e.Message, e.Snippet)
+	}
+	return fmt.Sprintf("syntax error at line %d, column %d (table \"%s\", row %d): %s: %s", e.Line, e.Column, e.Table, e.Row, e.Message, e.Snippet)
+}
@@ -0,0 +1,153 @@
+package csvt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Strings are written between double quotes with the escapes below, so a
+// cell never spans more than one line and its closing quote can be found
+// without knowing its content:
+//   - \" for a double quote and \\ for a backslash.
+//   - \n, \r, \t, \b and \f for their control characters.
+//   - \uXXXX for any other control character, also accepted on decode for
+//     any code point, with UTF-16 surrogate pairs.
+//
+// Files written before this grammar encoded double quotes as \', which is
+// still decoded as a double quote since it is never written anymore. Unknown
+// escapes are kept as written.
+
+// quote writes the string as a quoted cell.
+func quote(value string) string {
+	var builder strings.Builder
+	builder.Grow(len(value) + 2)
+
+	builder.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			builder.WriteString(`\"`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		case '\b':
+			builder.WriteString(`\b`)
+		case '\f':
+			builder.WriteString(`\f`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&builder, `\u%04x`, r)
+				continue
+			}
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteByte('"')
+
+	return builder.String()
+}
+
+// unquote reads the content of a quoted cell, without its quotes.
+func unquote(value string) string {
+	if !strings.ContainsRune(value, '\\') {
+		return value
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(value))
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case '"', '\'':
+			builder.WriteByte('"')
+		case '\\':
+			builder.WriteByte('\\')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 't':
+			builder.WriteByte('\t')
+		case 'b':
+			builder.WriteByte('\b')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			r, size := unescapeRune(value[i+1:])
+			if size == 0 {
+				builder.WriteString(`\u`)
+				continue
+			}
+			builder.WriteRune(r)
+			i += size
+		default:
+			builder.WriteByte('\\')
+			builder.WriteByte(value[i])
+		}
+	}
+
+	return builder.String()
+}
+
+// unescapeRune reads the hexadecimal digits following a \u escape, joining
+// surrogate pairs. It returns the number of bytes consumed, or zero when the
+// escape is malformed.
+func unescapeRune(value string) (rune, int) {
+	r, ok := parseHex(value)
+	if !ok {
+		return 0, 0
+	}
+
+	if !utf16.IsSurrogate(r) {
+		return r, 4
+	}
+
+	if len(value) >= 10 && value[4:6] == `\u` {
+		if low, ok := parseHex(value[6:]); ok {
+			if pair := utf16.DecodeRune(r, low); pair != utf8.RuneError {
+				return pair, 10
+			}
+		}
+	}
+
+	return utf8.RuneError, 4
+}
+
+func parseHex(value string) (rune, bool) {
+	if len(value) < 4 {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(value[:4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(code), true
+}
+
+// closingQuote returns the position of the quote closing the string opened
+// at the start of the value, skipping escaped characters, or -1.
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,215 @@
+package csvt
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	TAG_NAME       = "csv"
+	TAG_SKIP       = "-"
+	TAG_SEPARATOR  = ","
+	TAG_OMIT_EMPTY = "omitempty"
+	TAG_ALIAS      = "alias="
+	TAG_ALIAS_SEP  = "|"
+	TAG_TABLE      = "table"
+)
+
+// field describes how a struct field maps to a table column. It is shared by
+// the serializer, which writes the headers, and the deserializer, which looks
+// the columns up, so both directions agree on the column names.
+type field struct {
+	index     []int
+	name      string
+	aliases   []string
+	omitEmpty bool
+}
+
+func (f field) names() []string {
+	return append([]string{f.name}, f.aliases...)
+}
+
+var fieldCache sync.Map
+
+// structFields resolves the columns of a struct type. Only exported fields are
+// walked, and their "csv" tags are honored:
+//   - csv:"name" renames the column.
+//   - csv:"-" skips the field.
+//   - csv:",omitempty" writes empty values as a nil marker.
+//   - csv:",alias=old1|old2" accepts the listed column names on decode.
+//   - csv:",table" keeps an embedded struct as a field of its own.
+//
+// Fields of untagged embedded structs are promoted into the parent following
+// the encoding/json rules: the shallowest field wins a name conflict, then a
+// tagged one, and conflicts that remain ambiguous are dropped.
+func structFields(typ reflect.Type) []field {
+	if cached, ok := fieldCache.Load(typ); ok {
+		return cached.([]field)
+	}
+
+	candidates := collectFields(typ, []int{}, map[reflect.Type]bool{})
+
+	fields := []field{}
+	for _, candidate := range candidates {
+		if candidate.dominates(candidates) {
+			fields = append(fields, candidate.field)
+		}
+	}
+
+	cached, _ := fieldCache.LoadOrStore(typ, fields)
+	return cached.([]field)
+}
+
+type candidate struct {
+	field
+	tagged bool
+}
+
+func (c candidate) dominates(candidates []candidate) bool {
+	for _, other := range candidates {
+		if other.name != c.name || slices.Equal(other.index, c.index) {
+			continue
+		}
+		if len(other.index) < len(c.index) {
+			return false
+		}
+		if len(other.index) == len(c.index) && (other.tagged || !c.tagged) {
+			return false
+		}
+	}
+	return true
+}
+
+func collectFields(typ reflect.Type, index []int, visited map[reflect.Type]bool) []candidate {
+	visited[typ] = true
+	defer delete(visited, typ)
+
+	candidates := []candidate{}
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+
+		tag := structField.Tag.Get(TAG_NAME)
+		if tag == TAG_SKIP {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, TAG_SEPARATOR)
+		fieldIndex := append(slices.Clone(index), i)
+
+		if structField.Anonymous && name == "" && !hasTagOption(options, TAG_TABLE) {
+			embedded := structField.Type
+			if embedded.Kind() == reflect.Pointer && structField.IsExported() {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && !visited[embedded] {
+				candidates = append(candidates, collectFields(embedded, fieldIndex, visited)...)
+				continue
+			}
+		}
+
+		if !structField.IsExported() {
+			continue
+		}
+
+		candidate := candidate{
+			field: field{
+				index: fieldIndex,
+				name:  name,
+			},
+			tagged: name != "",
+		}
+
+		if name == "" {
+			candidate.name = structField.Name
+		}
+
+		for _, option := range strings.Split(options, TAG_SEPARATOR) {
+			switch {
+			case option == TAG_OMIT_EMPTY:
+				candidate.omitEmpty = true
+			case strings.HasPrefix(option, TAG_ALIAS):
+				aliases := strings.TrimPrefix(option, TAG_ALIAS)
+				candidate.aliases = strings.Split(aliases, TAG_ALIAS_SEP)
+			}
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates
+}
+
+func hasTagOption(options string, option string) bool {
+	return slices.Contains(strings.Split(options, TAG_SEPARATOR), option)
+}
+
+// fieldByIndex walks the index of a promoted field, allocating the nil
+// embedded pointers found on the way.
+func fieldByIndex(structure reflect.Value, index []int) reflect.Value {
+	for i, position := range index {
+		if i > 0 && structure.Kind() == reflect.Pointer {
+			if structure.IsNil() {
+				structure.Set(reflect.New(structure.Type().Elem()))
+			}
+			structure = structure.Elem()
+		}
+		structure = structure.Field(position)
+	}
+	return structure
+}
+
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return value.IsZero()
+	}
+	return false
+}
+
+// checkSupported reports types that cannot be represented in a table: kinds
+// with no textual form, and structs whose state is entirely unexported, which
+// would otherwise be written as empty rows and silently lose their content.
+// Structs whose fields are all skipped through tags are written as empty
+// rows, since skipping them is a choice of the user. Types with private state
+// opt in to being encoded by implementing Marshaler and Unmarshaler, which
+// are checked before this.
+func checkSupported(typ reflect.Type, path string) error {
+	switch typ.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer,
+		reflect.Complex64, reflect.Complex128, reflect.Uintptr:
+		return UnsupportedType(typ).at(path)
+	case reflect.Struct:
+		if typ.NumField() > 0 && !hasExported(typ, map[reflect.Type]bool{}) {
+			return UnsupportedType(typ).at(path)
+		}
+	}
+	return nil
+}
+
+// hasExported reports whether the struct has an exported field, walking the
+// unexported embedded structs whose fields are promoted.
+func hasExported(typ reflect.Type, visited map[reflect.Type]bool) bool {
+	visited[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		if structField.IsExported() {
+			return true
+		}
+		embedded := structField.Type
+		if structField.Anonymous && embedded.Kind() == reflect.Struct && !visited[embedded] {
+			if hasExported(embedded, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -7,29 +7,36 @@ import (
 )
 
 type group struct {
+	table    string
 	category category
 	headers  collection.Vector[string]
 	group    any
 }
 
-func newGroup[T any](category category, headers []string, grp T) group {
+func newGroup[T any](table string, category category, headers []string, grp T) group {
 	return group{
+		table:    table,
 		category: category,
 		headers:  *collection.VectorFromList(headers),
 		group:    grp,
 	}
 }
 
-func (r *group) findField(key string) (*node, bool) {
+// findField returns the node under the first header matching any of the
+// given keys, so renamed columns can still be found by their older names.
+func (r *group) findField(keys ...string) (*node, bool) {
 	switch v := r.group.(type) {
 	case []node:
-		index := r.headers.IndexOf(func(s string) bool {
-			return s == key
-		})
-		if index == -1 || index > len(v) {
-			return nil, false
+		for _, key := range keys {
+			index := r.headers.IndexOf(func(s string) bool {
+				return s == key
+			})
+			if index == -1 || index >= len(v) {
+				continue
+			}
+			return &v[index], true
 		}
-		return &v[index], true
+		return nil, false
 	default:
 		return nil, false
 	}
@@ -38,18 +45,36 @@ func (r *group) findField(key string) (*node, bool) {
 func (r *group) findFields() []collection.Pair[string, node] {
 	pairs := []collection.Pair[string, node]{}
 	switch v := r.group.(type) {
-	case map[string]node:
-		for k, v := range v {
-			pairs = append(pairs, collection.NewPair(k, v))
+	case []entry:
+		for _, e := range v {
+			pairs = append(pairs, collection.NewPair(e.key.text(), e.value))
 		}
 	case []node:
 		for i, v := range v {
-			pairs = append(pairs, collection.NewPair(strconv.Itoa(i), v))
+			key := strconv.Itoa(i)
+			if header, ok := r.headers.Get(i); ok && r.category == STR {
+				key = header
+			}
+			pairs = append(pairs, collection.NewPair(key, v))
 		}
 	}
 	return pairs
 }
 
+// findEntries returns the key and value pairs of a map row, in the order
+// they were written. Other rows are keyed by the names of their fields.
+func (r *group) findEntries() []entry {
+	if entries, ok := r.group.([]entry); ok {
+		return entries
+	}
+
+	entries := []entry{}
+	for _, p := range r.findFields() {
+		entries = append(entries, entry{key: fromNonPointer(p.Key()), value: p.Value()})
+	}
+	return entries
+}
+
 func (r *group) findValue() (*node, bool) {
 	switch v := r.group.(type) {
 	case node:
@@ -58,3 +83,26 @@ func (r *group) findValue() (*node, bool) {
 		return nil, false
 	}
 }
+
+// references returns the cells of the row that point to other rows.
+func (r *group) references() []node {
+	nodes := []node{}
+	switch v := r.group.(type) {
+	case []entry:
+		for _, e := range v {
+			nodes = append(nodes, e.key, e.value)
+		}
+	case []node:
+		nodes = append(nodes, v...)
+	case node:
+		nodes = append(nodes, v)
+	}
+
+	references := []node{}
+	for _, n := range nodes {
+		if n.index != -1 {
+			references = append(references, n)
+		}
+	}
+	return references
+}
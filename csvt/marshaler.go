@@ -0,0 +1,102 @@
+package csvt
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// Marshaler is implemented by types that encode themselves as a single CSVT
+// cell instead of a table row. The returned text is written as a string.
+type Marshaler interface {
+	MarshalCSVT() (string, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves from the text
+// produced by their Marshaler. UnmarshalCSVT must copy the text if it wishes
+// to retain it after returning.
+type Unmarshaler interface {
+	UnmarshalCSVT(text string) error
+}
+
+var (
+	marshalerType       = reflect.TypeFor[Marshaler]()
+	unmarshalerType     = reflect.TypeFor[Unmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// marshalText encodes a value through its Marshaler, falling back to
+// encoding.TextMarshaler. Methods declared on the pointer receiver are used
+// too, copying the value when it is not addressable. The boolean reports
+// whether the value implements any of the interfaces.
+func marshalText(value reflect.Value) (string, bool, error) {
+	typ := value.Type()
+	if !typ.Implements(marshalerType) && !typ.Implements(textMarshalerType) {
+		ptrType := reflect.PointerTo(typ)
+		if !ptrType.Implements(marshalerType) && !ptrType.Implements(textMarshalerType) {
+			return "", false, nil
+		}
+
+		if !value.CanAddr() {
+			pointer := reflect.New(typ)
+			pointer.Elem().Set(value)
+			value = pointer
+		} else {
+			value = value.Addr()
+		}
+	}
+
+	var text string
+	var err error
+	switch marshaler := value.Interface().(type) {
+	case Marshaler:
+		text, err = marshaler.MarshalCSVT()
+	case encoding.TextMarshaler:
+		var bytes []byte
+		bytes, err = marshaler.MarshalText()
+		text = string(bytes)
+	}
+
+	if err != nil {
+		return "", true, fmt.Errorf("marshal type \"%s\": %w", typ, err)
+	}
+
+	return text, true, nil
+}
+
+// unmarshalText decodes an inline string cell through the Unmarshaler of the
+// target type, falling back to encoding.TextUnmarshaler. Referenced cells are
+// not handled, so data written before a type gained its own encoding can
+// still be decoded from its table.
+func unmarshalText(typ reflect.Type, node *node, path string) (reflect.Value, bool, error) {
+	if typ.Kind() == reflect.Interface || node.index != -1 {
+		return reflect.Value{}, false, nil
+	}
+
+	ptrType := reflect.PointerTo(typ)
+	if !ptrType.Implements(unmarshalerType) && !ptrType.Implements(textUnmarshalerType) {
+		return reflect.Value{}, false, nil
+	}
+
+	text, ok := node.value.(string)
+	if !ok {
+		err := TypeMismatchf("string", node.typeName(), "%s", path).at(path)
+		return reflect.Value{}, true, err
+	}
+
+	var err error
+	pointer := reflect.New(typ)
+	switch unmarshaler := pointer.Interface().(type) {
+	case Unmarshaler:
+		err = unmarshaler.UnmarshalCSVT(text)
+	case encoding.TextUnmarshaler:
+		err = unmarshaler.UnmarshalText([]byte(text))
+	}
+
+	if err != nil {
+		return reflect.Value{}, true, InvalidValuef(path, "unmarshal type \"%s\": %w", typ, err)
+	}
+
+	return pointer.Elem(), true, nil
+}
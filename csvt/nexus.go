@@ -1,22 +1,111 @@
 package csvt
 
-import "github.com/Rafael24595/go-collections/collection"
+import (
+	"errors"
+	"unicode/utf8"
+)
 
 type nexus struct {
-	key   string
-	root  bool
-	nodes collection.Vector[group]
+	key     string
+	root    bool
+	line    int
+	headers []string
+	rows    []string
+	lines   []int
+	groups  map[int]*group
 }
 
-func newNexus(key string, root bool, nodes []group) nexus {
+func newNexus(key string, root bool, headers []string, rows []string, lines []int) nexus {
 	return nexus{
-		key:   key,
-		root:  root,
-		nodes: *collection.VectorFromList(nodes),
+		key:     key,
+		root:    root,
+		headers: headers,
+		rows:    rows,
+		lines:   lines,
+		groups:  make(map[int]*group),
 	}
 }
 
-func (r *nexus) get(position int) (*group, bool) {
-	group, ok := r.nodes.Get(position)
-	return &group, ok
+func (r *nexus) size() int {
+	return len(r.rows)
+}
+
+// get returns the row at the given position, parsing it on first access.
+// Secondary rows are kept once parsed since they may be referenced many
+// times, while root rows are parsed on every access so that walking the
+// root table does not retain it.
+func (r *nexus) get(position int) (*group, bool, error) {
+	if position < 0 || position >= len(r.rows) {
+		return nil, false, nil
+	}
+
+	if group, ok := r.groups[position]; ok {
+		return group, true, nil
+	}
+
+	group, err := parseRow(r.key, r.rows[position], r.headers)
+	if err != nil {
+		return nil, true, r.syntaxError(position, err)
+	}
+
+	if !r.root {
+		r.groups[position] = group
+	}
+
+	return group, true, nil
+}
+
+func (r *nexus) reference(position int) string {
+	return fromPointer(r.key, position).reference()
+}
+
+func (r *nexus) parse() error {
+	for i := range r.rows {
+		if _, _, err := r.get(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *nexus) syntaxError(position int, err error) *SyntaxError {
+	offset := 0
+	var rowErr *rowError
+	if errors.As(err, &rowErr) {
+		offset = rowErr.offset
+	}
+
+	column, snippet := locate(r.rows[position], offset)
+
+	return &SyntaxError{
+		Table:   r.key,
+		Row:     position,
+		Line:    r.lines[position],
+		Column:  column,
+		Snippet: snippet,
+		Message: err.Error(),
+	}
+}
+
+const snippetRadius = 20
+
+// locate converts a byte offset of the line into a 1-based column counted in
+// characters, and cuts the text around it.
+func locate(line string, offset int) (int, string) {
+	offset = min(max(offset, 0), len(line))
+	column := utf8.RuneCountInString(line[:offset]) + 1
+
+	runes := []rune(line)
+	start := max(column-1-snippetRadius, 0)
+	end := min(column-1+snippetRadius, len(runes))
+
+	snippet := string(runes[start:end])
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(runes) {
+		snippet = snippet + "..."
+	}
+
+	return column, snippet
 }
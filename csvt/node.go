@@ -1,12 +1,22 @@
 package csvt
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+)
 
 type node struct {
 	value interface{}
 	index int
 }
 
+// entry is a key and value pair of a map row. Keys are nodes too, so they
+// keep their parsed type and may reference a row.
+type entry struct {
+	key   node
+	value node
+}
+
 func fromPointer(value interface{}, index int) node {
 	return node{
 		value: value,
@@ -25,6 +35,39 @@ func fromEmpty() node {
 	return fromNonPointer("")
 }
 
+func fromNil() node {
+	return fromNonPointer(nil)
+}
+
+func (n node) isNil() bool {
+	return n.index == -1 && n.value == nil
+}
+
+// scalar returns the inline value with its natural Go type.
+func (n node) scalar() any {
+	if value, ok := n.value.(number); ok {
+		return value.natural()
+	}
+	return n.value
+}
+
+// typeName names the type of the inline value for error messages.
+func (n node) typeName() string {
+	return reflect.TypeOf(n.scalar()).Name()
+}
+
+// text returns the inline value as written, or the reference it holds.
+func (n node) text() string {
+	if n.index != -1 {
+		return n.reference()
+	}
+	return fmt.Sprintf("%v", n.value)
+}
+
 func (n node) key() string {
 	return fmt.Sprintf("%v", n.value)
 }
+
+func (n node) reference() string {
+	return fmt.Sprintf("%s%c%d", n.key(), PTR_SEPARATOR, n.index)
+}
@@ -0,0 +1,121 @@
+package csvt
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	FLOAT_NAN     = "NaN"
+	FLOAT_INF     = "+Inf"
+	FLOAT_NEG_INF = "-Inf"
+)
+
+var (
+	integerPattern = regexp.MustCompile(`^[+-]?\d+$`)
+	floatPattern   = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+)
+
+// number is a numeric cell kept as written, so it is converted to the exact
+// type of its target instead of going through int or float64, which would
+// lose the values that only fit in an uint64 or a float32.
+type number string
+
+func parseNumber(obj string) (number, bool) {
+	switch obj {
+	case FLOAT_NAN, FLOAT_INF, FLOAT_NEG_INF:
+		return number(obj), true
+	}
+	if integerPattern.MatchString(obj) || floatPattern.MatchString(obj) {
+		return number(obj), true
+	}
+	return "", false
+}
+
+func (n number) isInteger() bool {
+	return integerPattern.MatchString(string(n))
+}
+
+// natural returns the value with the type it has when no target type is
+// known: int, then int64 and uint64 for the integers that do not fit, and
+// float64 for everything else.
+func (n number) natural() any {
+	if n.isInteger() {
+		if value, err := strconv.ParseInt(string(n), 10, strconv.IntSize); err == nil {
+			return int(value)
+		}
+		if value, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return value
+		}
+		if value, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			return value
+		}
+	}
+	value, _ := strconv.ParseFloat(string(n), 64)
+	return value
+}
+
+// convert parses the number as the given numeric type. Integer targets only
+// accept integers, while float targets accept both.
+func (n number) convert(typ reflect.Type, path string) (reflect.Value, error) {
+	value := reflect.New(typ).Elem()
+	text := string(n)
+
+	var err error
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !n.isInteger() {
+			return reflect.Value{}, TypeMismatchf(typ.Name(), "float64", "%s", path).at(path)
+		}
+		var parsed int64
+		parsed, err = strconv.ParseInt(text, 10, typ.Bits())
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !n.isInteger() {
+			return reflect.Value{}, TypeMismatchf(typ.Name(), "float64", "%s", path).at(path)
+		}
+		var parsed uint64
+		parsed, err = strconv.ParseUint(strings.TrimPrefix(text, "+"), 10, typ.Bits())
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		var parsed float64
+		parsed, err = strconv.ParseFloat(text, typ.Bits())
+		value.SetFloat(parsed)
+	default:
+		found := reflect.TypeOf(n.natural()).Name()
+		return reflect.Value{}, TypeMismatchf(typ.Name(), found, "%s", path).at(path)
+	}
+
+	if err != nil {
+		return reflect.Value{}, InvalidValuef(path, "value %s does not fit in \"%s\"", text, typ)
+	}
+
+	return value, nil
+}
+
+// formatFloat writes the shortest representation that parses back to the
+// same value, keeping a decimal point or an exponent so it is never read as
+// an integer, and names the values that have no numeric form.
+func formatFloat(value float64, bits int) string {
+	switch {
+	case math.IsNaN(value):
+		return FLOAT_NAN
+	case math.IsInf(value, 1):
+		return FLOAT_INF
+	case math.IsInf(value, -1):
+		return FLOAT_NEG_INF
+	}
+
+	text := strconv.FormatFloat(value, 'g', -1, bits)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
+
+func isNumeric(typ reflect.Type) bool {
+	return isCommonType(typ) && typ.Kind() != reflect.String && typ.Kind() != reflect.Bool
+}
@@ -0,0 +1,187 @@
+package csvt
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// TableOrder selects the order in which the secondary tables are written
+// after the root table.
+type TableOrder int
+
+const (
+	TABLE_ORDER_FIRST_SEEN TableOrder = iota
+	TABLE_ORDER_ALPHABETICAL
+)
+
+// tableKeys returns the tables in the order they are written: the root table
+// first, then the rest as configured.
+func (s *csvtSerializer) tableKeys() []string {
+	keys := slices.Clone(s.order)
+	if s.opts.TableOrder == TABLE_ORDER_ALPHABETICAL {
+		slices.Sort(keys)
+	}
+
+	if index := slices.Index(keys, s.rootKey); index > 0 {
+		keys = slices.Delete(keys, index, index+1)
+		keys = slices.Insert(keys, 0, s.rootKey)
+	}
+
+	return keys
+}
+
+// mapKeys returns the keys of the map sorted with the configured comparator,
+// or in their natural order, so equal maps always produce the same row. Keys
+// that tie are ordered by their content and then by the content of their
+// values, so the order does not depend on how the map is walked.
+func (s *csvtSerializer) mapKeys(entity reflect.Value) []reflect.Value {
+	keys := entity.MapKeys()
+
+	compare := compareKeys
+	if s.opts.MapKeyOrder != nil {
+		compare = func(a, b reflect.Value) int {
+			return s.opts.MapKeyOrder(a.Interface(), b.Interface())
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		if order := compare(a, b); order != 0 {
+			return order
+		}
+		if order := cmp.Compare(content(a), content(b)); order != 0 {
+			return order
+		}
+		return cmp.Compare(content(entity.MapIndex(a)), content(entity.MapIndex(b)))
+	})
+
+	return keys
+}
+
+// compareKeys orders strings, numbers and booleans by value. Keys of other
+// kinds, or of different types behind an interface, fall back to their type
+// and content.
+func compareKeys(a, b reflect.Value) int {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+
+	if !a.IsValid() || !b.IsValid() {
+		return cmp.Compare(boolRank(a.IsValid()), boolRank(b.IsValid()))
+	}
+
+	if a.Type() != b.Type() {
+		return cmp.Compare(a.Type().String(), b.Type().String())
+	}
+
+	switch a.Kind() {
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.Bool:
+		return cmp.Compare(boolRank(a.Bool()), boolRank(b.Bool()))
+	}
+
+	return cmp.Compare(content(a), content(b))
+}
+
+// content renders a value as a text that only depends on what it holds:
+// pointers are followed instead of printing their address, maps are sorted
+// and cycles are cut. Types with their own text encoding are rendered
+// through it.
+func content(value reflect.Value) string {
+	builder := &strings.Builder{}
+	writeContent(builder, value, map[uintptr]bool{})
+	return builder.String()
+}
+
+func writeContent(builder *strings.Builder, value reflect.Value, visiting map[uintptr]bool) {
+	if !value.IsValid() {
+		builder.WriteRune(PTR_NIL)
+		return
+	}
+
+	if value.CanInterface() {
+		kind := value.Kind()
+		if (kind != reflect.Pointer && kind != reflect.Interface) || !value.IsNil() {
+			if text, ok, err := marshalText(value); ok && err == nil {
+				builder.WriteString(quote(text))
+				return
+			}
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			builder.WriteRune(PTR_NIL)
+			return
+		}
+		builder.WriteString(value.Elem().Type().String())
+		writeContent(builder, value.Elem(), visiting)
+	case reflect.Pointer:
+		if value.IsNil() {
+			builder.WriteRune(PTR_NIL)
+			return
+		}
+		address := value.Pointer()
+		if visiting[address] {
+			builder.WriteRune(PTR_HEADER)
+			return
+		}
+		visiting[address] = true
+		defer delete(visiting, address)
+
+		builder.WriteRune(PTR_HEADER)
+		writeContent(builder, value.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if i > 0 {
+				builder.WriteRune(STR_SEPARATOR)
+			}
+			writeContent(builder, value.Field(i), visiting)
+		}
+		builder.WriteRune(STR_CLOSING)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if i > 0 {
+				builder.WriteRune(ARR_SEPARATOR)
+			}
+			writeContent(builder, value.Index(i), visiting)
+		}
+		builder.WriteRune(ARR_CLOSING)
+	case reflect.Map:
+		entries := []string{}
+		for _, key := range value.MapKeys() {
+			entry := &strings.Builder{}
+			writeContent(entry, key, visiting)
+			entry.WriteRune(MAP_LINKER)
+			writeContent(entry, value.MapIndex(key), visiting)
+			entries = append(entries, entry.String())
+		}
+		slices.Sort(entries)
+		builder.WriteString(strings.Join(entries, string(MAP_SEPARATOR)))
+		builder.WriteRune(MAP_CLOSING)
+	case reflect.String:
+		builder.WriteString(quote(value.String()))
+	default:
+		fmt.Fprintf(builder, "%v", value)
+	}
+}
+
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -8,50 +8,40 @@ import (
 	"strings"
 )
 
-func parseTable(table string) (*nexus, error) {
-	root := false
-
-	items := []group{}
-
-	fragments := strings.Split(table, "\n")
-
-	if strings.Contains(fragments[0], string(TBL_HEAD_ROOT)+string(TBL_HEAD_ROOT)) {
-		root = true
+func parseHeaders(row string) []string {
+	re := regexp.MustCompile(`^[A-Za-z0-9]+->\s?`)
+	row = re.ReplaceAllString(row, "")
+	if row == "" {
+		return []string{}
 	}
+	return strings.Split(row, string(HEA_SEPARATOR))
+}
 
-	re := regexp.MustCompile(`/\*\*\s|///\s`)
-	name := re.ReplaceAllString(fragments[0], "")
-
-	heads := parseHeaders(fragments[1])
+// rowError locates a parse failure by its byte offset within the row, so it
+// can be reported with the position of the row in the document.
+type rowError struct {
+	offset  int
+	message string
+}
 
-	for _, v := range fragments[2:] {
-		if len(v) == 0 {
-			continue
-		}
-		row, err := parseRow(v, heads)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, *row)
+func errorAt(offset int, format string, args ...any) *rowError {
+	return &rowError{
+		offset:  offset,
+		message: fmt.Sprintf(format, args...),
 	}
+}
 
-	result := newNexus(name, root, items)
-
-	return &result, nil
+func (e *rowError) Error() string {
+	return e.message
 }
 
-func parseHeaders(row string) []string {
-	re := regexp.MustCompile(`[A-Za-z0-9]+->\s`)
-	row = re.ReplaceAllString(row, "")
+func parseRow(table string, line string, header []string) (*group, error) {
+	re := regexp.MustCompile(`^\d+-> ?`)
+	row := re.ReplaceAllString(line, "")
+	prefix := len(line) - len(row)
 	if row == "" {
-		return []string{}
+		return nil, errorAt(prefix, "empty row")
 	}
-	return strings.Split(row, string(HEA_SEPARATOR))
-}
-
-func parseRow(row string, header []string) (*group, error) {
-	re := regexp.MustCompile(`\d+-> `)
-	row = re.ReplaceAllString(row, "")
 
 	instance := categoryOf(row, len(header) != 0)
 
@@ -67,14 +57,19 @@ func parseRow(row string, header []string) (*group, error) {
 	case OBJ:
 		group, err = parseObject(row)
 	default:
-		err = fmt.Errorf("row type not recognized: \n%s", row)
+		err = errorAt(0, "row type not recognized")
 	}
 
 	if err != nil {
-		return nil, err
+		var rowErr *rowError
+		if !errors.As(err, &rowErr) {
+			rowErr = errorAt(0, "%s", err.Error())
+		}
+		rowErr.offset += prefix
+		return nil, rowErr
 	}
 
-	result := newGroup(instance, header, group)
+	result := newGroup(table, instance, header, group)
 	return &result, nil
 }
 
@@ -95,39 +90,53 @@ func categoryOf(row string, header bool) category {
 	return STR
 }
 
-func parseMap(row string) (map[string]node, error) {
-	mapp := map[string]node{}
+func parseMap(row string) ([]entry, error) {
+	mapp := []entry{}
 
 	if rune(row[len(row)-1]) != MAP_CLOSING {
-		return nil, errors.New("invalid map closing character")
+		return nil, errorAt(len(row)-1, "invalid map closing character")
 	}
 
 	row = row[:len(row)-1]
 
 	buffer := row
 	for len(buffer) > 0 {
+		offset := len(row) - len(buffer)
+
 		var index int
 		if buffer[0] == '"' {
-			index = strings.Index(buffer[1:], "\"") + 2
+			closing := closingQuote(buffer)
+			if closing == -1 {
+				return nil, errorAt(offset, "unterminated string")
+			}
+			index = closing + 1
 		} else {
 			index = strings.Index(buffer, string(MAP_LINKER))
 		}
 
-		if index == -1 {
-			return nil, errors.New("undefined value")
+		if index == -1 || index >= len(buffer) || rune(buffer[index]) != MAP_LINKER {
+			return nil, errorAt(offset, "undefined value")
 		}
 
 		key := buffer[:index]
 		buffer = buffer[index+1:]
 
-		node, err := parseObject(key)
+		keyNode, err := parseObject(key)
 		if err != nil {
-			return nil, err
+			return nil, errorAt(offset, "%s", err.Error())
+		}
+
+		offset = len(row) - len(buffer)
+		if len(buffer) == 0 {
+			return nil, errorAt(offset, "undefined value")
 		}
-		key = node.key()
 
 		if buffer[0] == '"' {
-			index = strings.Index(buffer[1:], "\"") + 1
+			closing := closingQuote(buffer)
+			if closing == -1 {
+				return nil, errorAt(offset, "unterminated string")
+			}
+			index = closing
 			if index < len(buffer)-1 && buffer[index+1] == byte(MAP_SEPARATOR) {
 				index = index + 1
 			} else {
@@ -140,7 +149,7 @@ func parseMap(row string) (map[string]node, error) {
 		var content string
 		if index != -1 {
 			if len(buffer) >= index && rune(buffer[index]) != MAP_SEPARATOR {
-				return nil, errors.New("invalid map entry")
+				return nil, errorAt(offset+index, "invalid map entry")
 			}
 			content = buffer[:index]
 			buffer = buffer[index+1:]
@@ -149,11 +158,11 @@ func parseMap(row string) (map[string]node, error) {
 			buffer = ""
 		}
 
-		node, err = parseObject(content)
+		valueNode, err := parseObject(content)
 		if err != nil {
-			return nil, err
+			return nil, errorAt(offset, "%s", err.Error())
 		}
-		mapp[key] = node
+		mapp = append(mapp, entry{key: keyNode, value: valueNode})
 	}
 
 	return mapp, nil
@@ -171,16 +180,22 @@ func parseList(row string, separator, closing rune) ([]node, error) {
 	lst := []node{}
 
 	if rune(row[len(row)-1]) != closing {
-		return nil, errors.New("invalid list closing character")
+		return nil, errorAt(len(row)-1, "invalid list closing character")
 	}
 
 	row = row[:len(row)-1]
 
 	buffer := row
 	for len(buffer) > 0 {
+		offset := len(row) - len(buffer)
+
 		var index int
 		if buffer[0] == '"' {
-			index = strings.Index(buffer[1:], "\"") + 2
+			closing := closingQuote(buffer)
+			if closing == -1 {
+				return nil, errorAt(offset, "unterminated string")
+			}
+			index = closing + 1
 			if len(buffer) == index {
 				index = -1
 			}
@@ -191,7 +206,7 @@ func parseList(row string, separator, closing rune) ([]node, error) {
 		var content string
 		if index != -1 {
 			if len(buffer) >= index && rune(buffer[index]) != separator {
-				return nil, errors.New("invalid list separator character")
+				return nil, errorAt(offset+index, "invalid list separator character")
 			}
 			content = buffer[:index]
 			buffer = buffer[index+1:]
@@ -202,7 +217,7 @@ func parseList(row string, separator, closing rune) ([]node, error) {
 
 		node, err := parseObject(content)
 		if err != nil {
-			return nil, err
+			return nil, errorAt(offset, "%s", err.Error())
 		}
 		lst = append(lst, node)
 	}
@@ -214,9 +229,12 @@ func parseObject(obj string) (node, error) {
 	if len(obj) == 0 {
 		return fromEmpty(), nil
 	}
-	if v, i, ok, err := isPointer(obj); ok {
+	if obj == string(PTR_NIL) {
+		return fromNil(), nil
+	}
+	if v, i, ok, err := isPointer(obj); ok || err != nil {
 		if err != nil {
-			return node{}, nil
+			return node{}, err
 		}
 		return fromPointer(v, i), nil
 	}
@@ -230,16 +248,11 @@ func parseObject(obj string) (node, error) {
 	if lower == "true" {
 		return fromNonPointer(true), nil
 	}
-	if strings.Contains(obj, ".") {
-		if v, err := strconv.ParseFloat(obj, 64); err == nil {
-			return fromNonPointer(v), nil
-		}
-	}
-	if v, err := strconv.Atoi(obj); err == nil {
+	if v, ok := parseNumber(obj); ok {
 		return fromNonPointer(v), nil
 	}
 
-	return node{}, fmt.Errorf("type not recognized: \n%s", obj)
+	return node{}, fmt.Errorf("type not recognized: %s", obj)
 }
 
 func isPointer(obj string) (string, int, bool, error) {
@@ -247,14 +260,18 @@ func isPointer(obj string) (string, int, bool, error) {
 		return obj, 0, false, nil
 	}
 
-	fragments := strings.Split(obj[1:], string(PTR_SEPARATOR))
+	// The index follows the last separator, since table names may contain it.
+	separator := strings.LastIndexByte(obj, byte(PTR_SEPARATOR))
+	if separator == -1 {
+		return "", 0, false, fmt.Errorf("reference \"%s\" has no index", obj)
+	}
 
-	key := fragments[0]
-	index := 0
+	key := obj[1:separator]
+	fragment := obj[separator+1:]
 
-	index, err := strconv.Atoi(fragments[1])
+	index, err := strconv.Atoi(fragment)
 	if err != nil {
-		err := fmt.Errorf("index \"%s\" type not recognized: %s", fragments[1], err.Error())
+		err := fmt.Errorf("index \"%s\" type not recognized: %s", fragment, err.Error())
 		return "", 0, false, err
 	}
 
@@ -262,19 +279,8 @@ func isPointer(obj string) (string, int, bool, error) {
 }
 
 func isString(obj string) (string, bool) {
-	len := len(obj)
-	if obj[0] == '"' && obj[len-1] == '"' {
-		fixed := strings.ReplaceAll(obj[1:len-1], "\\'", "\"")
-		replacer := strings.NewReplacer(
-			"\\\\", "\\",
-			"\\\"", "\"",
-			"\\n", "\n",
-			"\\r", "\r",
-			"\\t", "\t",
-			"\\b", "\b",
-			"\\f", "\f")
-		fixed = replacer.Replace(fixed)
-		return fixed, true
+	if len(obj) >= 2 && obj[0] == '"' && closingQuote(obj) == len(obj)-1 {
+		return unquote(obj[1 : len(obj)-1]), true
 	}
 	return obj, false
 }
@@ -0,0 +1,40 @@
+package csvt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Paths locate a value within the object graph for error reporting, using
+// the Go notation: Lang[1].Release.Version, Attributes["oop"] or Tags[3].
+
+func rootPath(key string, index int) string {
+	name, _, _ := strings.Cut(key, "&")
+	return indexPath(name, index)
+}
+
+func fieldPath(path, name string) string {
+	return path + "." + name
+}
+
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+func entryPath(path string, key node) string {
+	if key.index != -1 {
+		return fmt.Sprintf("%s[%c%s]", path, PTR_HEADER, key.reference())
+	}
+	return keyPath(path, reflect.ValueOf(key.scalar()))
+}
+
+func keyPath(path string, key reflect.Value) string {
+	if !key.IsValid() {
+		return fmt.Sprintf("%s[%c]", path, PTR_NIL)
+	}
+	if key.Kind() == reflect.String {
+		return fmt.Sprintf("%s[%q]", path, key.String())
+	}
+	return fmt.Sprintf("%s[%v]", path, key.Interface())
+}
@@ -1,40 +1,110 @@
 package csvt
 
 import (
+	"bufio"
+	"bytes"
+	"io"
 	"strings"
 )
 
 type reader struct {
-	structures map[string][]string
+	tables   map[string]nexus
+	repeated []repetition
+	current  *nexus
+	line     int
 }
 
 func newReader() *reader {
 	return &reader{
-		structures: make(map[string][]string),
+		tables:   make(map[string]nexus),
+		repeated: []repetition{},
 	}
 }
 
 func (r *reader) read(data []byte) (*table, error) {
-	tables := map[string]nexus{}
+	return r.readFrom(bytes.NewReader(data))
+}
 
-	buffer := string(data)
-	buffer = strings.ReplaceAll(buffer, "\r\n", "\n")
+// readFrom scans the input line by line, splitting it into tables whose rows
+// are kept unparsed until they are requested.
+func (r *reader) readFrom(source io.Reader) (*table, error) {
+	buffer := bufio.NewReader(source)
 
-	fragments := strings.Split(buffer, "\n\n")
-	for _, v := range fragments {
-		table := strings.TrimSpace(v)
-		if table == "" {
-			continue
+	for {
+		line, err := buffer.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
 		}
 
-		nexus, err := parseTable(table)
-		if err != nil {
-			return nil, err
+		r.line++
+		line = strings.TrimRight(line, "\r\n")
+		if strings.TrimSpace(line) != "" {
+			if err := r.readLine(line); err != nil {
+				return nil, err
+			}
 		}
 
-		tables[nexus.key] = *nexus
+		if err == io.EOF {
+			break
+		}
 	}
 
-	tbl := newTable(tables)
+	r.flush()
+
+	tbl := newTable(r.tables, r.repeated)
 	return &tbl, nil
 }
+
+func (r *reader) readLine(line string) error {
+	if strings.HasPrefix(line, HEADER_ROOT) || strings.HasPrefix(line, HEADER_REGULAR) {
+		r.flush()
+
+		root := strings.HasPrefix(line, HEADER_ROOT)
+		name := strings.TrimSpace(line[len(HEADER_ROOT):])
+
+		nexus := newNexus(name, root, []string{}, []string{}, []int{})
+		nexus.line = r.line
+		r.current = &nexus
+
+		return nil
+	}
+
+	if r.current == nil {
+		column, snippet := locate(line, 0)
+		return &SyntaxError{
+			Row:     -1,
+			Line:    r.line,
+			Column:  column,
+			Snippet: snippet,
+			Message: "row found outside of a table",
+		}
+	}
+
+	if strings.HasPrefix(line, string(TBL_INDEX_HEAD)) {
+		r.current.headers = parseHeaders(line)
+		return nil
+	}
+
+	r.current.rows = append(r.current.rows, line)
+	r.current.lines = append(r.current.lines, r.line)
+
+	return nil
+}
+
+func (r *reader) flush() {
+	if r.current == nil {
+		return
+	}
+
+	if previous, ok := r.tables[r.current.key]; ok {
+		r.repeated = append(r.repeated, repetition{
+			key:   r.current.key,
+			root:  r.current.root || previous.root,
+			line:  r.current.line,
+			first: previous.line,
+		})
+	}
+
+	r.tables[r.current.key] = *r.current
+	r.current = nil
+}
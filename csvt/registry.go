@@ -0,0 +1,127 @@
+package csvt
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type enum struct {
+	names  map[any]string
+	values map[string]reflect.Value
+}
+
+var enums sync.Map
+
+// RegisterEnum registers the names used to write the values of a named
+// primitive type, so Level(2) is written as "warn" and read back from it.
+// Values missing from the mapping are still written as their base type, and
+// cells holding the base representation are still accepted on decode.
+//
+// It panics if the type is not a primitive or if a name is repeated, as
+// registrations are expected to happen on package initialization.
+//
+// Example:
+//   csvt.RegisterEnum(map[Level]string{
+//     Debug: "debug",
+//     Warn:  "warn",
+//   })
+func RegisterEnum[T comparable](names map[T]string) {
+	typ := reflect.TypeFor[T]()
+	if !isCommonType(typ) {
+		panic(fmt.Sprintf("csvt: enum type \"%s\" must have a primitive underlying type", typ))
+	}
+
+	enum := &enum{
+		names:  make(map[any]string),
+		values: make(map[string]reflect.Value),
+	}
+
+	for value, name := range names {
+		if _, exists := enum.values[name]; exists {
+			panic(fmt.Sprintf("csvt: enum type \"%s\" has a duplicated name \"%s\"", typ, name))
+		}
+		enum.names[value] = name
+		enum.values[name] = reflect.ValueOf(value)
+	}
+
+	enums.Store(typ, enum)
+}
+
+func enumName(value reflect.Value) (string, bool) {
+	registered, ok := enums.Load(value.Type())
+	if !ok {
+		return "", false
+	}
+
+	name, ok := registered.(*enum).names[value.Interface()]
+	return name, ok
+}
+
+// makeEnum reads the names written for registered enums. Strings that are
+// not registered names are left to the base conversion when the enum is
+// string based, since unregistered values are written as their base value.
+func makeEnum(typ reflect.Type, node *node, path string) (reflect.Value, bool, error) {
+	registered, ok := enums.Load(typ)
+	if !ok || node.index != -1 {
+		return reflect.Value{}, false, nil
+	}
+
+	name, ok := node.value.(string)
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	value, ok := registered.(*enum).values[name]
+	if !ok && typ.Kind() == reflect.String {
+		return reflect.Value{}, false, nil
+	}
+	if !ok {
+		return reflect.Value{}, true, InvalidValuef(path, "unknown name \"%s\" for enum type \"%s\"", name, typ)
+	}
+
+	return value, true, nil
+}
+
+var types sync.Map
+
+// Register records the concrete type of the given value, so interface-typed
+// fields (including "any") referencing its table decode into that type. Since
+// every reference carries the table name, which includes the type name and
+// package hash, "$Circle&hash_0" decodes into a Circle once it is registered.
+// Registering a pointer (&Circle{}) decodes the rows into pointers.
+//
+// It panics if another type is already registered for the same table.
+//
+// Example:
+//   csvt.Register(Circle{})
+//   csvt.Register(&Square{})
+func Register(value any) {
+	typ := reflect.TypeOf(value)
+	if typ == nil {
+		panic("csvt: cannot register a nil value")
+	}
+
+	base := typ
+	for base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+
+	key := tableKey(base)
+	if key == COMMON_ARRAY || key == COMMON_MAP {
+		panic(fmt.Sprintf("csvt: type \"%s\" has no table of its own", typ))
+	}
+
+	registered, loaded := types.LoadOrStore(key, typ)
+	if loaded && registered != typ {
+		panic(fmt.Sprintf("csvt: table \"%s\" is already registered for type \"%s\"", key, registered))
+	}
+}
+
+func registeredType(key string) (reflect.Type, bool) {
+	registered, ok := types.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return registered.(reflect.Type), true
+}
@@ -1,15 +1,30 @@
 package csvt
 
-import "github.com/Rafael24595/go-collections/collection"
+import (
+	"slices"
+
+	"github.com/Rafael24595/go-collections/collection"
+)
 
 type table struct {
-	nexus collection.Dictionary[string, nexus]
+	nexus    collection.Dictionary[string, nexus]
+	repeated []repetition
+}
+
+// repetition records a table header found again after the table was
+// defined. The later definition replaces the earlier one.
+type repetition struct {
+	key   string
+	root  bool
+	line  int
+	first int
 }
 
-func newTable(nexus map[string]nexus) table {
+func newTable(nexus map[string]nexus, repeated []repetition) table {
 	tbl := collection.DictionaryFromMap(nexus)
 	return table{
-		nexus: *tbl,
+		nexus:    *tbl,
+		repeated: repeated,
 	}
 }
 
@@ -20,14 +35,45 @@ func (r *table) root() (*nexus, bool) {
 	return &nexus, ok
 }
 
-func (r *table) Find(node *node) (*group, bool) {
+func (r *table) Find(node *node) (*group, bool, error) {
 	value, exists := r.nexus.Get(node.key())
 	if !exists {
-		return nil, false
+		return nil, false, nil
 	}
 	if node.index != -1 {
-		group, ok := value.nodes.Get(node.index)
-		return &group, ok
+		return value.get(node.index)
+	}
+	return nil, false, nil
+}
+
+// parse parses every row of every table, reporting the first malformed one.
+func (r *table) parse() error {
+	for _, key := range r.nexus.Keys() {
+		value, _ := r.nexus.Get(key)
+		if err := value.parse(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// malformed parses every secondary row, returning the syntax error of each
+// malformed one sorted by table and row.
+func (r *table) malformed() []*SyntaxError {
+	keys := r.nexus.Keys()
+	slices.Sort(keys)
+
+	errs := []*SyntaxError{}
+	for _, key := range keys {
+		value, _ := r.nexus.Get(key)
+		if value.root {
+			continue
+		}
+		for position := range value.rows {
+			if _, _, err := value.get(position); err != nil {
+				errs = append(errs, IsSyntaxError(err))
+			}
+		}
 	}
-	return nil, false
+	return errs
 }
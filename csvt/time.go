@@ -0,0 +1,78 @@
+package csvt
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const DEFAULT_TIME_LAYOUT = time.RFC3339Nano
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+)
+
+// serializeTime writes time.Time values as strings formatted with the
+// configured layout, and time.Duration values as integer nanoseconds or,
+// optionally, as Go duration strings.
+func (s *csvtSerializer) serializeTime(value reflect.Value) (string, bool) {
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	switch value.Type() {
+	case timeType:
+		layout := s.opts.TimeLayout
+		if layout == "" {
+			layout = DEFAULT_TIME_LAYOUT
+		}
+		return sprintf("%v", value.Interface().(time.Time).Format(layout)), true
+	case durationType:
+		duration := time.Duration(value.Int())
+		if s.opts.DurationString {
+			return sprintf("%v", duration.String()), true
+		}
+		return strconv.FormatInt(int64(duration), 10), true
+	default:
+		return "", false
+	}
+}
+
+// makeTime reads the cells written by serializeTime. Durations are accepted
+// in both of their forms, regardless of the options used to write them.
+func (d *csvtDeserializer) makeTime(typ reflect.Type, node *node, path string) (reflect.Value, bool, error) {
+	if node.index != -1 || (typ != timeType && typ != durationType) {
+		return reflect.Value{}, false, nil
+	}
+
+	switch value := node.value.(type) {
+	case string:
+		if typ == durationType {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				return reflect.Value{}, true, InvalidValue(path, err)
+			}
+			return reflect.ValueOf(duration), true, nil
+		}
+
+		layout := d.opts.TimeLayout
+		if layout == "" {
+			layout = DEFAULT_TIME_LAYOUT
+		}
+
+		instant, err := time.Parse(layout, value)
+		if err != nil {
+			return reflect.Value{}, true, InvalidValue(path, err)
+		}
+		return reflect.ValueOf(instant), true, nil
+	case number:
+		if typ == durationType && value.isInteger() {
+			duration, err := value.convert(durationType, path)
+			return duration, true, err
+		}
+	}
+
+	err := TypeMismatchf(typ.Name(), node.typeName(), "%s", path).at(path)
+	return reflect.Value{}, true, err
+}
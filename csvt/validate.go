@@ -0,0 +1,231 @@
+package csvt
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IssueKind classifies the problems reported by Validate.
+type IssueKind string
+
+const (
+	ISSUE_SYNTAX      IssueKind = "SYNTAX"
+	ISSUE_ROOT        IssueKind = "ROOT"
+	ISSUE_TABLE       IssueKind = "TABLE"
+	ISSUE_INDEX       IssueKind = "INDEX"
+	ISSUE_PREFIX      IssueKind = "PREFIX"
+	ISSUE_ARITY       IssueKind = "ARITY"
+	ISSUE_UNREACHABLE IssueKind = "UNREACHABLE"
+)
+
+// Issue is a single problem found by Validate. Row is -1 and Line is 0 when
+// the issue does not concern a specific row.
+type Issue struct {
+	Kind    IssueKind
+	Table   string
+	Row     int
+	Line    int
+	Message string
+}
+
+func (i Issue) String() string {
+	switch {
+	case i.Row != -1:
+		return fmt.Sprintf("%s: line %d (table \"%s\", row %d): %s", i.Kind, i.Line, i.Table, i.Row, i.Message)
+	case i.Line != 0:
+		return fmt.Sprintf("%s: line %d: %s", i.Kind, i.Line, i.Message)
+	default:
+		return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+	}
+}
+
+// Report lists every issue found by Validate, grouped by table.
+type Report struct {
+	Issues []Issue
+}
+
+// Valid reports whether the document has no issues.
+func (r *Report) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *Report) add(kind IssueKind, nexus *nexus, row int, format string, args ...any) {
+	issue := Issue{
+		Kind:    kind,
+		Row:     -1,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if nexus != nil {
+		issue.Table = nexus.key
+	}
+	if nexus != nil && row != -1 {
+		issue.Row = row
+		issue.Line = nexus.lines[row]
+	}
+	r.Issues = append(r.Issues, issue)
+}
+
+var rowPrefix = regexp.MustCompile(`^(\d+)->`)
+
+// Validate checks the integrity of a CSVT document without decoding it:
+//   - the document defines exactly one root table and no table is
+//     defined twice.
+//   - every row parses, its prefix matches its position and structure rows
+//     have one cell per header.
+//   - every reference points to an existing table and a row within range.
+//   - every row can be reached from the root table, except the empty
+//     collection rows written at position 0. Reachability is only checked
+//     when every row parses.
+//
+// Returns a report listing every issue found instead of the first one.
+//
+// Example:
+//   report := csvt.Validate(data)
+//   for _, issue := range report.Issues {
+//     fmt.Println(issue)
+//   }
+func Validate(data []byte) *Report {
+	report := &Report{
+		Issues: []Issue{},
+	}
+
+	tables, err := newReader().read(data)
+	if err != nil {
+		if syntax := IsSyntaxError(err); syntax != nil {
+			report.Issues = append(report.Issues, Issue{
+				Kind:    ISSUE_SYNTAX,
+				Table:   syntax.Table,
+				Row:     syntax.Row,
+				Line:    syntax.Line,
+				Message: syntax.Message,
+			})
+		} else {
+			report.add(ISSUE_SYNTAX, nil, -1, "%s", err.Error())
+		}
+		return report
+	}
+
+	for _, repeated := range tables.repeated {
+		kind, name := ISSUE_TABLE, "table"
+		if repeated.root {
+			kind, name = ISSUE_ROOT, "root table"
+		}
+		report.Issues = append(report.Issues, Issue{
+			Kind:    kind,
+			Table:   repeated.key,
+			Row:     -1,
+			Line:    repeated.line,
+			Message: fmt.Sprintf("%s \"%s\" is repeated, first defined at line %d", name, repeated.key, repeated.first),
+		})
+	}
+
+	keys := tables.nexus.Keys()
+	slices.Sort(keys)
+
+	roots := []string{}
+	references := map[string][]node{}
+	for _, key := range keys {
+		nexus, _ := tables.nexus.Get(key)
+		if nexus.root {
+			roots = append(roots, key)
+		}
+		for position := range nexus.rows {
+			references[nexus.reference(position)] = validateRow(report, tables, &nexus, position)
+		}
+	}
+
+	switch len(roots) {
+	case 0:
+		report.add(ISSUE_ROOT, nil, -1, "root table is not defined")
+		return report
+	case 1:
+	default:
+		report.add(ISSUE_ROOT, nil, -1, "%d root tables defined: %s", len(roots), strings.Join(roots, ", "))
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Kind == ISSUE_SYNTAX {
+			return report
+		}
+	}
+
+	reached := map[string]bool{}
+	pending := []string{}
+	for _, key := range roots {
+		nexus, _ := tables.nexus.Get(key)
+		for position := range nexus.rows {
+			pending = append(pending, nexus.reference(position))
+		}
+	}
+
+	for len(pending) > 0 {
+		reference := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if reached[reference] {
+			continue
+		}
+		reached[reference] = true
+		for _, node := range references[reference] {
+			pending = append(pending, node.reference())
+		}
+	}
+
+	for _, key := range keys {
+		nexus, _ := tables.nexus.Get(key)
+		for position := range nexus.rows {
+			if reached[nexus.reference(position)] || position == 0 && isEmptyRow(nexus.rows[0]) {
+				continue
+			}
+			report.add(ISSUE_UNREACHABLE, &nexus, position, "row is not referenced from the root table")
+		}
+	}
+
+	return report
+}
+
+// validateRow checks a single row, returning the references it holds that
+// point to existing rows.
+func validateRow(report *Report, tables *table, nexus *nexus, position int) []node {
+	line := nexus.rows[position]
+
+	prefix := rowPrefix.FindStringSubmatch(line)
+	if prefix == nil {
+		report.add(ISSUE_PREFIX, nexus, position, "row has no index prefix")
+	} else if index, _ := strconv.Atoi(prefix[1]); index != position {
+		report.add(ISSUE_PREFIX, nexus, position, "row prefix %d does not match its position %d", index, position)
+	}
+
+	group, _, err := nexus.get(position)
+	if err != nil {
+		report.add(ISSUE_SYNTAX, nexus, position, "%s", IsSyntaxError(err).Message)
+		return []node{}
+	}
+
+	if cells, ok := group.group.([]node); ok && group.category == STR && len(nexus.headers) != 0 && len(cells) != len(nexus.headers) {
+		report.add(ISSUE_ARITY, nexus, position, "row has %d cells but the table has %d headers", len(cells), len(nexus.headers))
+	}
+
+	valid := []node{}
+	for _, node := range group.references() {
+		target, ok := tables.nexus.Get(node.key())
+		if !ok {
+			report.add(ISSUE_TABLE, nexus, position, "reference \"%s\" points to an undefined table", node.reference())
+			continue
+		}
+		if node.index < 0 || node.index >= target.size() {
+			report.add(ISSUE_INDEX, nexus, position, "reference \"%s\" is out of range, the table has %d rows", node.reference(), target.size())
+			continue
+		}
+		valid = append(valid, node)
+	}
+
+	return valid
+}
+
+func isEmptyRow(line string) bool {
+	content := strings.TrimSpace(rowPrefix.ReplaceAllString(line, ""))
+	return content == string(ARR_CLOSING) || content == string(MAP_CLOSING) || content == `""`
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestArray_RoundTrip(t *testing.T) {
+	sample := support.Sample{
+		Id:     [4]byte{0xde, 0xad, 0xbe, 0xef},
+		Point:  [3]float64{1.5, -2, 0},
+		Labels: [2]string{"x", "y"},
+		Grid:   [2][2]int{{1, 2}, {3, 4}},
+	}
+
+	result, err := csvt.Marshal(sample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded support.Sample
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded != sample {
+		t.Errorf("expected %+v, got %+v", sample, decoded)
+	}
+}
+
+func TestArray_LengthMismatch(t *testing.T) {
+	sample := support.Sample{
+		Point: [3]float64{1.5, 2.5, 3.5},
+	}
+
+	result, err := csvt.Marshal(sample)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	broken := strings.Replace(string(result), "1.5,2.5,3.5|", "1.5,2.5|", 1)
+
+	var decoded support.Sample
+	err = csvt.Unmarshal([]byte(broken), &decoded)
+
+	mismatch := csvt.IsLengthMismatch(err)
+	if mismatch == nil {
+		t.Fatalf("expected LengthMismatch error, got: %v", err)
+	}
+	if mismatch.Expected != 3 || mismatch.Found != 2 || mismatch.Path != "Sample[0].Point" {
+		t.Errorf("unexpected LengthMismatch error: %+v", mismatch)
+	}
+}
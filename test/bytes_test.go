@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestBytes_Inline(t *testing.T) {
+	blob := support.Blob{
+		Name:   "payload",
+		Data:   []byte("hello, csvt"),
+		Digest: [4]byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	cases := []struct {
+		name     string
+		encoding csvt.BytesEncoding
+		expRow   string
+	}{
+		{"base64", csvt.BYTES_BASE64, `"payload";"aGVsbG8sIGNzdnQ=";"3q2+7w==":`},
+		{"hex", csvt.BYTES_HEX, `"payload";"68656c6c6f2c2063737674";"deadbeef":`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := csvt.MarshalOpts(csvt.MarshalOptions{Compact: true, BytesEncoding: c.encoding}, blob)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.Contains(string(result), c.expRow) || strings.Contains(string(result), "common-array") {
+				t.Errorf("expected inline bytes %s, got:\n%s", c.expRow, result)
+			}
+
+			var decoded support.Blob
+			err = csvt.UnmarshalOpts(result, &decoded, csvt.UnmarshalOptions{BytesEncoding: c.encoding})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(decoded, blob) {
+				t.Errorf("expected %+v, got %+v", blob, decoded)
+			}
+		})
+	}
+}
+
+func TestBytes_CompactBlobs(t *testing.T) {
+	blob := support.Blob{Name: "same", Data: bytes.Repeat([]byte{1, 2, 3}, 100)}
+	library := support.Library{
+		Blobs: []support.Blob{blob, blob, blob},
+	}
+
+	result, err := csvt.Marshal(library)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if count := strings.Count(string(result), `"same";`); count != 1 {
+		t.Errorf("expected a single blob row, got %d:\n%s", count, result)
+	}
+
+	var decoded support.Library
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, library) {
+		t.Errorf("expected %+v, got %+v", library, decoded)
+	}
+}
+
+func TestBytes_LegacyArray(t *testing.T) {
+	result, err := csvt.Marshal(support.Blob{Name: "legacy", Data: []byte{7}, Digest: [4]byte{1, 2, 3, 4}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	legacy := strings.Replace(string(result), `"legacy";"Bw==";"AQIDBA==":`, `"legacy";$common-array_1;$common-array_2:`, 1)
+	legacy += "\n/// common-array\nH-> \n0-> |\n1-> 7,8,9|\n2-> 4,3,2,1|\n"
+
+	var decoded support.Blob
+	if err := csvt.Unmarshal([]byte(legacy), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, legacy)
+	}
+
+	if !bytes.Equal(decoded.Data, []byte{7, 8, 9}) || decoded.Digest != [4]byte{4, 3, 2, 1} {
+		t.Errorf("unexpected legacy blob: %+v", decoded)
+	}
+}
+
+func TestBytes_NamedElements(t *testing.T) {
+	queue := support.Queue{
+		List:  []support.Priority{3, 1, 2},
+		Fixed: [2]support.Priority{5, 4},
+	}
+
+	result, err := csvt.Marshal(queue)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(result), "common-array") {
+		t.Errorf("expected named elements written as arrays, got:\n%s", result)
+	}
+
+	var decoded support.Queue
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, queue) {
+		t.Errorf("expected %+v, got %+v", queue, decoded)
+	}
+}
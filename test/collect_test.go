@@ -0,0 +1,114 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestUnmarshal_CollectErrorsSkip(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table_invalid.csvt")
+
+	var langs []support.Lang
+	opts := csvt.UnmarshalOptions{
+		OnRowError: csvt.ROW_ERROR_SKIP,
+	}
+
+	err := csvt.UnmarshalOpts(data, &langs, opts)
+	if err == nil {
+		t.Fatalf("expected the invalid rows to be reported")
+	}
+
+	if len(langs) != 1 || langs[0].Name != "Go" {
+		t.Fatalf("expected only the valid row, got: %+v", langs)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Fatalf("expected two joined errors, got: %v", err)
+	}
+
+	rows := []int{}
+	for _, failure := range joined.Unwrap() {
+		invalid := csvt.IsInvalidRow(failure)
+		if invalid == nil {
+			t.Fatalf("expected InvalidRow error, got: %v", failure)
+		}
+		rows = append(rows, invalid.Row)
+	}
+
+	if rows[0] != 1 || rows[1] != 2 {
+		t.Errorf("expected rows [1 2], got %v", rows)
+	}
+
+	mismatch := csvt.IsTypeMismatch(err)
+	if mismatch == nil || mismatch.Path != "Lang[1].Release.Version" {
+		t.Errorf("expected TypeMismatch at 'Lang[1].Release.Version', got: %v", mismatch)
+	}
+
+	var syntax *csvt.SyntaxError
+	if !errors.As(err, &syntax) || syntax.Row != 2 {
+		t.Errorf("expected SyntaxError at row 2, got: %v", syntax)
+	}
+}
+
+func TestUnmarshal_CollectErrorsZero(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table_invalid.csvt")
+
+	var langs []support.Lang
+	opts := csvt.UnmarshalOptions{
+		OnRowError: csvt.ROW_ERROR_ZERO,
+	}
+
+	err := csvt.UnmarshalOpts(data, &langs, opts)
+	if err == nil {
+		t.Fatalf("expected the invalid rows to be reported")
+	}
+
+	if len(langs) != 3 || langs[0].Name != "Go" || langs[1].Name != "" || langs[2].Name != "" {
+		t.Fatalf("expected the invalid rows as zero values, got: %+v", langs)
+	}
+}
+
+func TestUnmarshal_AbortOnFirstError(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table_invalid.csvt")
+
+	var langs []support.Lang
+	err := csvt.Unmarshal(data, &langs)
+
+	if csvt.IsSyntaxError(err) == nil || csvt.IsInvalidRow(err) != nil {
+		t.Fatalf("expected a single SyntaxError, got: %v", err)
+	}
+}
+
+func TestUnmarshal_CollectUnreferencedSyntaxError(t *testing.T) {
+	data := string(support.LoadFile(t, "../support/lang_table.csvt"))
+	data = strings.TrimRight(data, "\n") + "\n3-> \"broken|\n"
+
+	var langs []support.Lang
+	opts := csvt.UnmarshalOptions{
+		OnRowError: csvt.ROW_ERROR_SKIP,
+	}
+
+	err := csvt.UnmarshalOpts([]byte(data), &langs, opts)
+	if err == nil {
+		t.Fatalf("expected the unreferenced malformed row to be reported")
+	}
+
+	if csvt.IsInvalidRow(err) != nil {
+		t.Errorf("expected no root row to fail, got: %v", err)
+	}
+
+	syntax := csvt.IsSyntaxError(err)
+	if syntax == nil || syntax.Row != 3 {
+		t.Fatalf("expected SyntaxError at row 3, got: %v", err)
+	}
+
+	var strict []support.Lang
+	if csvt.IsSyntaxError(csvt.Unmarshal([]byte(data), &strict)) == nil {
+		t.Errorf("expected the default mode to report the same row")
+	}
+}
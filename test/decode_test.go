@@ -119,4 +119,120 @@ func TestUnmarshalDefaultMissingField(t *testing.T) {
 	if result[0].Release.Stable != expStable {
 		t.Errorf("expected Stable '%v', got '%v'", expStable, result[0].Release.Stable)
 	}
-}
\ No newline at end of file
+}
+
+func TestUnmarshalDocument(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	var result []map[string]any
+	err := csvt.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expLen := 2
+	if len(result) != expLen {
+		t.Fatalf("expected %d items, got %d", expLen, len(result))
+	}
+
+	lang1 := result[0]
+
+	expName := "Go"
+	if lang1["Name"] != expName {
+		t.Errorf("expected Name '%s', got '%v'", expName, lang1["Name"])
+	}
+
+	release, ok := lang1["Release"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected Release document, got %#v", lang1["Release"])
+	}
+	expVersion := "1.25.3"
+	if release["Version"] != expVersion || release["Stable"] != true {
+		t.Errorf("unexpected Release: %v", release)
+	}
+
+	tags, ok := lang1["Tags"].([]any)
+	if !ok || len(tags) != 2 || tags[0] != "go" || tags[1] != "golang" {
+		t.Errorf("unexpected Tags: %#v", lang1["Tags"])
+	}
+
+	attributes, ok := lang1["Attributes"].(map[string]any)
+	if !ok || attributes["oop"] != "some" {
+		t.Errorf("unexpected Attributes: %#v", lang1["Attributes"])
+	}
+
+	var single any
+	err = csvt.Unmarshal(data, &single)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	document, ok := single.(map[string]any)
+	if !ok || document["Name"] != expName {
+		t.Errorf("unexpected document: %#v", single)
+	}
+}
+
+func TestUnmarshalDocumentCyclic(t *testing.T) {
+	root := &support.Node{Name: "root"}
+	root.Children = []*support.Node{{Name: "leaf", Parent: root}}
+
+	data, err := csvt.Marshal(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result []any
+	err = csvt.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tree := result[0].(map[string]any)
+	leaf := tree["Children"].([]any)[0].(map[string]any)
+	if leaf["Name"] != "leaf" || leaf["Parent"].(map[string]any)["Name"] != "root" {
+		t.Errorf("unexpected leaf document: %v", leaf["Name"])
+	}
+}
+
+func TestUnmarshalRequireAllFields(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table_missing_field.csvt")
+
+	var result []support.Lang
+	opts := csvt.UnmarshalOptions{
+		RequireAllFields: true,
+	}
+
+	err := csvt.UnmarshalOpts(data, &result, opts)
+
+	missing := csvt.IsMissingField(err)
+	if missing == nil || missing.Field != "Release" || missing.Path != "Lang[0].Release" {
+		t.Fatalf("expected MissingField error for 'Lang[0].Release', but got: %v", err)
+	}
+}
+
+func TestUnmarshalDisallowUnknownFields(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table_unknown_field.csvt")
+
+	var result []support.Lang
+	err := csvt.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatalf("unexpected error with unknown fields allowed: %v", err)
+	}
+
+	opts := csvt.UnmarshalOptions{
+		DisallowUnknownFields: true,
+	}
+
+	err = csvt.UnmarshalOpts(data, &result, opts)
+
+	unknown := csvt.IsUnknownField(err)
+	if unknown == nil {
+		t.Fatalf("expected UnknownField error, but got: %v", err)
+	}
+
+	expTable := "Release&5a5fb74a27bca302170bf2d87c53fdf2dd358d03"
+	if unknown.Table != expTable || unknown.Field != "Codename" || unknown.Path != "Lang[0].Release.Codename" {
+		t.Errorf("unexpected UnknownField error: %+v", unknown)
+	}
+}
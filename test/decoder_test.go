@@ -0,0 +1,97 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestDecoder_Next(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	decoder := csvt.NewDecoder(bytes.NewReader(data))
+
+	names := []string{}
+	for {
+		var lang support.Lang
+		err := decoder.Next(&lang)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names = append(names, lang.Name)
+	}
+
+	if len(names) != 2 || names[0] != "Go" || names[1] != "Zig" {
+		t.Errorf("unexpected decoded names: %v", names)
+	}
+
+	var lang support.Lang
+	if err := decoder.Next(&lang); err != io.EOF {
+		t.Errorf("expected io.EOF after the last row, got: %v", err)
+	}
+}
+
+func TestDecoder_RowsStopEarly(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	broken := strings.Replace(string(data), `1-> "Zig";`, `1-> "Zig;`, 1)
+	decoder := csvt.NewDecoder(strings.NewReader(broken))
+
+	for lang, err := range csvt.Rows[*support.Lang](decoder) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if lang.Name != "Go" || lang.Release.Version != "1.25.3" {
+			t.Errorf("unexpected first row: %+v", lang)
+		}
+		break
+	}
+
+	count := 0
+	for _, err := range csvt.Rows[support.Lang](decoder) {
+		if err == nil {
+			t.Fatalf("expected error for the malformed second row")
+		}
+		count++
+	}
+
+	if count != 1 {
+		t.Errorf("expected a single error, got %d", count)
+	}
+}
+
+func TestDecoder_SelfReferencedRoot(t *testing.T) {
+	root := &support.Node{Name: "root"}
+	child := &support.Node{Name: "child", Parent: root}
+	root.Children = []*support.Node{child}
+
+	result, err := csvt.Marshal(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoder := csvt.NewDecoder(bytes.NewReader(result))
+
+	names := []string{}
+	for node, err := range csvt.Rows[support.Node](decoder) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if node.Parent != nil {
+			names = append(names, node.Parent.Name+"/"+node.Name)
+		} else {
+			names = append(names, node.Name)
+		}
+	}
+
+	if len(names) != 2 || names[0] != "root" || names[1] != "root/child" {
+		t.Errorf("expected [root root/child], got %v", names)
+	}
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestDocument_DecodeRow(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	document, err := csvt.Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if document.Len() != 2 {
+		t.Fatalf("expected 2 rows, got %d", document.Len())
+	}
+
+	for range 2 {
+		var last support.Lang
+		if err := document.DecodeRow(1, &last); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if last.Name != "Zig" {
+			t.Errorf("expected 'Zig', got '%s'", last.Name)
+		}
+	}
+
+	var first *support.Lang
+	if err := document.DecodeRow(0, &first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Name != "Go" || first.Release.Version != "1.25.3" {
+		t.Errorf("unexpected first row: %+v", first)
+	}
+}
+
+func TestDocument_DecodeRowOutOfRange(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	document, err := csvt.Parse(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var lang support.Lang
+	if err := document.DecodeRow(2, &lang); err == nil {
+		t.Errorf("expected error for a row out of range")
+	}
+	if err := document.DecodeRow(-1, &lang); err == nil {
+		t.Errorf("expected error for a negative row")
+	}
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestMarshal_EmbeddedPromotion(t *testing.T) {
+	article := support.Article{
+		Audit: support.Audit{
+			CreatedBy: "rafael",
+			UpdatedBy: "admin",
+		},
+		Title: "CSVT",
+	}
+
+	result, err := csvt.Marshal(article)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "H-> CreatedBy;UpdatedBy;Title\n") {
+		t.Errorf("expected promoted headers, got: %s", output)
+	}
+	if strings.Contains(output, "Audit&") {
+		t.Errorf("expected no Audit table, got: %s", output)
+	}
+
+	var drafts []support.Draft
+	err = csvt.Unmarshal(result, &drafts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(drafts) != 1 || drafts[0].Audit == nil {
+		t.Fatalf("expected embedded pointer to be allocated, got: %+v", drafts)
+	}
+	if drafts[0].CreatedBy != "rafael" || drafts[0].UpdatedBy != "admin" || drafts[0].Title != "CSVT" {
+		t.Errorf("unexpected decoded draft: %+v", drafts[0])
+	}
+}
+
+func TestMarshal_EmbeddedTable(t *testing.T) {
+	archive := support.Archive{
+		Audit: support.Audit{
+			CreatedBy: "rafael",
+		},
+		Title: "CSVT",
+	}
+
+	result, err := csvt.Marshal(archive)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "H-> Audit;Title\n") || !strings.Contains(output, "$Audit&") {
+		t.Errorf("expected embedded struct as a separate table, got: %s", output)
+	}
+
+	var decoded support.Archive
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.CreatedBy != "rafael" || decoded.Title != "CSVT" {
+		t.Errorf("unexpected decoded archive: %+v", decoded)
+	}
+}
+
+func TestMarshal_EmbeddedNilPointer(t *testing.T) {
+	result, err := csvt.Marshal(support.Draft{Title: "CSVT"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(result), "~;~;\"CSVT\":") {
+		t.Errorf("expected nil markers for fields of a nil embedded pointer, got: %s", result)
+	}
+}
@@ -75,3 +75,89 @@ func TestMarshal_LangStructure(t *testing.T) {
 		t.Errorf("expected common-map pointer references")
 	}
 }
+
+func TestMarshal_UnexportedFields(t *testing.T) {
+	account := support.NewAccount("rafael", "secret")
+
+	result, err := csvt.Marshal(account)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "H-> Name\n") {
+		t.Errorf("expected only exported headers, got: %s", output)
+	}
+	if strings.Contains(output, account.Password()) {
+		t.Errorf("expected unexported field to be skipped, got: %s", output)
+	}
+
+	var decoded support.Account
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Name != account.Name || decoded.Password() != "" {
+		t.Errorf("unexpected decoded account: %+v", decoded)
+	}
+}
+
+func TestMarshal_UnsupportedTypes(t *testing.T) {
+	_, err := csvt.Marshal(support.Channel{Name: "events"})
+	if unsupported := csvt.IsUnsupportedType(err); unsupported == nil {
+		t.Fatalf("expected UnsupportedType error for channel field, got: %v", err)
+	}
+
+	_, err = csvt.Marshal(support.Session{})
+	unsupported := csvt.IsUnsupportedType(err)
+	expType := "support.Credentials"
+	if unsupported == nil || unsupported.Type != expType {
+		t.Fatalf("expected UnsupportedType error for '%s', got: %v", expType, err)
+	}
+}
+
+func TestMarshal_UnexportedStateHook(t *testing.T) {
+	vault := support.Vault{Name: "main", Token: support.NewToken("s3cr3t")}
+
+	result, err := csvt.Marshal(vault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(result), `"main";"s3cr3t":`) {
+		t.Errorf("expected the private state written through its marshaler, got: %s", result)
+	}
+
+	var decoded support.Vault
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded != vault {
+		t.Errorf("expected %+v, got %+v", vault, decoded)
+	}
+}
+
+func TestMarshal_AllFieldsSkipped(t *testing.T) {
+	value := support.HasSkipped{Name: "draft", S: support.AllSkipped{Draft: "hidden"}}
+
+	result, err := csvt.Marshal(value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(string(result), "hidden") {
+		t.Errorf("expected skipped fields not to be written, got: %s", result)
+	}
+
+	var decoded support.HasSkipped
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Name != value.Name || decoded.S != (support.AllSkipped{}) {
+		t.Errorf("unexpected decoded value: %+v", decoded)
+	}
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestEncoder_StreamRoots(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	encoder := csvt.NewEncoder(buffer, csvt.MarshalOptions{Compact: true})
+
+	langs := []support.Lang{
+		{Name: "Go", Release: support.Release{Version: "1.25.3", Stable: true}, Tags: []string{"go"}},
+		{Name: "Zig", Release: support.Release{Version: "0.15.1"}, Tags: []string{"zig"}},
+		{Name: "Odin", Release: support.Release{Version: "1.25.3", Stable: true}},
+	}
+
+	for _, lang := range langs {
+		err := encoder.Encode(lang)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if buffer.Len() != 0 {
+		t.Fatalf("expected nothing written before closing, got: %s", buffer.String())
+	}
+
+	err := encoder.Close()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buffer.String()
+
+	if strings.Count(output, "/** Lang&") != 1 {
+		t.Errorf("expected a single root table, got: %s", output)
+	}
+	if strings.Count(output, "\"1.25.3\"") != 1 {
+		t.Errorf("expected compact rows across Encode calls, got: %s", output)
+	}
+
+	var decoded []support.Lang
+	err = csvt.Unmarshal(buffer.Bytes(), &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(decoded) != len(langs) {
+		t.Fatalf("expected %d items, got %d", len(langs), len(decoded))
+	}
+	for i, lang := range langs {
+		if decoded[i].Name != lang.Name || decoded[i].Release != lang.Release {
+			t.Errorf("expected item %d to be %+v, got %+v", i, lang, decoded[i])
+		}
+	}
+
+	err = encoder.Encode(langs[0])
+	if err == nil {
+		t.Errorf("expected error when encoding on a closed encoder, but got nil")
+	}
+}
+
+func TestEncoder_ErrorIsSticky(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	encoder := csvt.NewEncoder(buffer, csvt.MarshalOptions{Compact: true})
+
+	if err := encoder.Encode(support.Relay{Name: "valid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := encoder.Encode(support.Relay{Name: "broken", Target: &support.Channel{Name: "events"}})
+	if csvt.IsUnsupportedType(err) == nil {
+		t.Fatalf("expected UnsupportedType error, got: %v", err)
+	}
+
+	if err := encoder.Encode(support.Relay{Name: "after"}); csvt.IsUnsupportedType(err) == nil {
+		t.Errorf("expected the first error on later encodes, got: %v", err)
+	}
+
+	if err := encoder.Close(); csvt.IsUnsupportedType(err) == nil {
+		t.Errorf("expected the first error on close, got: %v", err)
+	}
+
+	if buffer.Len() != 0 {
+		t.Errorf("expected nothing written after a failed encode, got:\n%s", buffer.String())
+	}
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestMarshal_NamedPrimitives(t *testing.T) {
+	log := support.Log{
+		Message:  "started",
+		Status:   support.Open,
+		Level:    support.Warn,
+		Priority: 3,
+	}
+
+	result, err := csvt.Marshal(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "\"started\";\"open\";2;3;0:") {
+		t.Errorf("expected named primitives inline, got: %s", output)
+	}
+	if strings.Contains(output, "Status&") || strings.Contains(output, "Level&") {
+		t.Errorf("expected no tables for named primitives, got: %s", output)
+	}
+
+	var decoded support.Log
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded != log {
+		t.Errorf("expected %+v, got %+v", log, decoded)
+	}
+}
+
+func TestMarshal_RegisteredEnum(t *testing.T) {
+	csvt.RegisterEnum(map[support.Severity]string{
+		support.Minor:    "minor",
+		support.Major:    "major",
+		support.Critical: "critical",
+	})
+
+	log := support.Log{
+		Message:  "started",
+		Status:   support.Closed,
+		Severity: support.Critical,
+	}
+
+	result, err := csvt.Marshal(log)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "\"started\";\"closed\";0;0;\"critical\":") {
+		t.Errorf("expected enum name, got: %s", output)
+	}
+
+	var decoded support.Log
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Severity != support.Critical {
+		t.Errorf("expected Severity %v, got %v", support.Critical, decoded.Severity)
+	}
+
+	data := []byte(strings.Replace(output, "\"critical\"", "\"blocker\"", 1))
+	err = csvt.Unmarshal(data, &decoded)
+	invalid := csvt.IsInvalidValue(err)
+	if invalid == nil || invalid.Path != "Log[0].Severity" {
+		t.Fatalf("expected InvalidValue at 'Log[0].Severity' for unknown enum name, got: %v", err)
+	}
+}
+
+func TestUnmarshal_RegisteredStringEnumBaseValues(t *testing.T) {
+	csvt.RegisterEnum(map[support.Stage]string{
+		support.Planned: "planning",
+		support.Review:  "in-review",
+	})
+
+	tickets := []support.Ticket{
+		{Title: "named", Stage: support.Review},
+		{Title: "other", Stage: support.Stage("other")},
+		{Title: "zero"},
+	}
+
+	result, err := csvt.Marshal(tickets[0], tickets[1], tickets[2])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+	for _, row := range []string{`"named";"in-review":`, `"other";"other":`, `"zero";"":`} {
+		if !strings.Contains(output, row) {
+			t.Errorf("expected row %s, got: %s", row, output)
+		}
+	}
+
+	var decoded []support.Ticket
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(decoded) != len(tickets) {
+		t.Fatalf("expected %d tickets, got %+v", len(tickets), decoded)
+	}
+	for i := range tickets {
+		if decoded[i] != tickets[i] {
+			t.Errorf("expected %+v, got %+v", tickets[i], decoded[i])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestEscape_RoundTrip(t *testing.T) {
+	values := []string{
+		`He said "hi"`,
+		`C:\temp\new`,
+		`it\'s`,
+		`literal \n and \u0041`,
+		"line\nbreak\r\ttab",
+		"bell\u0007 and delete\u007f",
+		"héllo ☃ 𝄞",
+		`a;b,c=d^e|f:`,
+		`$Release_0`,
+		`~`,
+		`"`,
+		`\`,
+		``,
+	}
+
+	lang := support.Lang{
+		Name:       strings.Join(values, ""),
+		Tags:       values,
+		Attributes: map[string]string{},
+	}
+	for _, value := range values {
+		lang.Attributes[value] = value
+	}
+
+	result, err := csvt.Marshal(lang)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, line := range strings.Split(string(result), "\n") {
+		if line != "" && !strings.HasPrefix(line, "/") && !strings.Contains(line, "-> ") {
+			t.Fatalf("expected every cell on a single line, got: %q", line)
+		}
+	}
+
+	var decoded support.Lang
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, result)
+	}
+
+	if decoded.Name != lang.Name {
+		t.Errorf("expected name %q, got %q", lang.Name, decoded.Name)
+	}
+	if !slices.Equal(decoded.Tags, values) {
+		t.Errorf("expected tags %q, got %q", values, decoded.Tags)
+	}
+	for _, value := range values {
+		if decoded.Attributes[value] != value {
+			t.Errorf("expected attribute %q, got %q", value, decoded.Attributes[value])
+		}
+	}
+}
+
+func TestEscape_Legacy(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	legacy := strings.NewReplacer(
+		`"Go"`, `"\'Go\' \u00e9 \ud834\udd1e"`,
+		`"zig"`, `"\d"`,
+	).Replace(string(data))
+
+	var langs []support.Lang
+	if err := csvt.Unmarshal([]byte(legacy), &langs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if langs[0].Name != `"Go" é 𝄞` {
+		t.Errorf("expected legacy quotes and unicode escapes, got %q", langs[0].Name)
+	}
+	if langs[1].Tags[0] != `\d` {
+		t.Errorf("expected unknown escape kept as written, got %q", langs[1].Tags[0])
+	}
+}
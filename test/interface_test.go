@@ -0,0 +1,111 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestMarshal_InterfaceFields(t *testing.T) {
+	csvt.Register(support.Circle{})
+	csvt.Register(&support.Square{})
+
+	canvas := support.Canvas{
+		Name:       "draft",
+		Background: support.Circle{Radius: 1.5},
+		Shapes: []support.Shape{
+			&support.Square{Side: 2.5},
+			support.Circle{Radius: 0.5},
+			nil,
+		},
+		Meta: "v1",
+	}
+
+	result, err := csvt.Marshal(canvas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "$Circle&") || !strings.Contains(output, "$Square&") {
+		t.Errorf("expected concrete type references, got: %s", output)
+	}
+
+	var decoded support.Canvas
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if background, ok := decoded.Background.(support.Circle); !ok || background.Radius != 1.5 {
+		t.Errorf("unexpected Background: %#v", decoded.Background)
+	}
+
+	if len(decoded.Shapes) != 3 {
+		t.Fatalf("expected 3 shapes, got %d", len(decoded.Shapes))
+	}
+	if square, ok := decoded.Shapes[0].(*support.Square); !ok || square.Side != 2.5 {
+		t.Errorf("unexpected first shape: %#v", decoded.Shapes[0])
+	}
+	if circle, ok := decoded.Shapes[1].(support.Circle); !ok || circle.Radius != 0.5 {
+		t.Errorf("unexpected second shape: %#v", decoded.Shapes[1])
+	}
+	if decoded.Shapes[2] != nil {
+		t.Errorf("expected nil third shape, got: %#v", decoded.Shapes[2])
+	}
+
+	if decoded.Meta != "v1" {
+		t.Errorf("expected Meta 'v1', got: %#v", decoded.Meta)
+	}
+}
+
+func TestUnmarshal_InterfaceNotRegistered(t *testing.T) {
+	data := []byte(`/** Canvas&9f9e0c8f6f58f26bad6d95082e34f120acfd4e0a
+H-> Name;Background;Shapes;Meta
+0-> "draft";$Triangle&9f9e0c8f6f58f26bad6d95082e34f120acfd4e0a_0;~;~:
+
+/// Triangle&9f9e0c8f6f58f26bad6d95082e34f120acfd4e0a
+H-> Base;Height
+0-> 1;2:
+`)
+
+	var decoded support.Canvas
+	err := csvt.Unmarshal(data, &decoded)
+	if err == nil {
+		t.Fatalf("expected error for unregistered type, but got nil")
+	}
+}
+
+func TestMarshal_InterfaceEqualRows(t *testing.T) {
+	csvt.Register(support.Circle{})
+	csvt.Register(support.Hexagon{})
+
+	drawing := support.Drawing{
+		Shapes: []support.Shape{
+			support.Circle{Radius: 2},
+			support.Hexagon{Side: 2},
+		},
+	}
+
+	result, err := csvt.Marshal(drawing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(result), "$Hexagon&") {
+		t.Errorf("expected a reference to the hexagon table, got: %s", result)
+	}
+
+	var decoded support.Drawing
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, drawing) {
+		t.Errorf("expected %+v, got %+v", drawing, decoded)
+	}
+}
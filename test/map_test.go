@@ -0,0 +1,85 @@
+package test
+
+import (
+	"math"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func sampleCatalog() support.Catalog {
+	return support.Catalog{
+		ByCode:    map[int]string{7: "seven", -1: "minus one"},
+		ByCounter: map[uint64]bool{math.MaxUint64: true, 0: false},
+		ByFlag:    map[bool]int{true: 1, false: 0},
+		ByRatio:   map[float64]string{0.5: "half", 1e6: "million"},
+		ByAddress: map[netip.Addr]string{
+			netip.MustParseAddr("10.0.0.1"): "gateway",
+			netip.MustParseAddr("::1"):      "loopback",
+		},
+		ByCoord: map[support.Coord]string{
+			{X: 1, Y: 2}: "a",
+			{X: 3, Y: 4}: "b",
+		},
+		ByLevel: map[support.Level]string{support.Debug: "verbose", support.Warn: "quiet"},
+	}
+}
+
+func TestMap_NonStringKeys(t *testing.T) {
+	catalog := sampleCatalog()
+
+	result, err := csvt.Marshal(catalog)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report := csvt.Validate(result); !report.Valid() {
+		t.Errorf("expected a valid document, got: %v", report.Issues)
+	}
+
+	var decoded support.Catalog
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, result)
+	}
+
+	if !reflect.DeepEqual(decoded, catalog) {
+		t.Errorf("expected %+v, got %+v", catalog, decoded)
+	}
+}
+
+func TestMap_KeysAsText(t *testing.T) {
+	result, err := csvt.Marshal(sampleCatalog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var document map[string]any
+	if err := csvt.Unmarshal(result, &document); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	codes, ok := document["ByCode"].(map[string]any)
+	if !ok || codes["7"] != "seven" || codes["-1"] != "minus one" {
+		t.Errorf("expected integer keys as text, got %#v", document["ByCode"])
+	}
+}
+
+func TestMap_KeyErrorPath(t *testing.T) {
+	result, err := csvt.Marshal(support.Catalog{ByCode: map[int]string{7: "seven"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		ByCode map[bool]string
+	}
+	err = csvt.Unmarshal(result, &decoded)
+
+	mismatch := csvt.IsTypeMismatch(err)
+	if mismatch == nil || mismatch.Path != "Catalog[0].ByCode[7]" {
+		t.Errorf("expected TypeMismatch at 'Catalog[0].ByCode[7]', got: %v", err)
+	}
+}
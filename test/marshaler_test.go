@@ -0,0 +1,63 @@
+package test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestMarshal_CustomMarshalers(t *testing.T) {
+	invoice := support.Invoice{
+		Id:       "INV-001",
+		Total:    support.Money{Cents: 1250, Currency: "EUR"},
+		Discount: &support.Money{Cents: 100, Currency: "EUR"},
+		Server:   net.ParseIP("192.168.0.1"),
+	}
+
+	result, err := csvt.Marshal(invoice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "\"INV-001\";\"1250 EUR\";\"100 EUR\";\"192.168.0.1\":") {
+		t.Errorf("expected custom types as single cells, got: %s", output)
+	}
+	if strings.Contains(output, "Money&") || strings.Contains(output, "common-array") {
+		t.Errorf("expected no tables for custom types, got: %s", output)
+	}
+
+	var decoded support.Invoice
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Total != invoice.Total {
+		t.Errorf("expected Total %+v, got %+v", invoice.Total, decoded.Total)
+	}
+	if decoded.Discount == nil || *decoded.Discount != *invoice.Discount {
+		t.Errorf("expected Discount %+v, got %+v", invoice.Discount, decoded.Discount)
+	}
+	if !decoded.Server.Equal(invoice.Server) {
+		t.Errorf("expected Server %v, got %v", invoice.Server, decoded.Server)
+	}
+}
+
+func TestUnmarshal_CustomUnmarshalerError(t *testing.T) {
+	data := []byte(`/** Invoice&9f9e0c8f6f58f26bad6d95082e34f120acfd4e0a
+H-> Id;Total;Discount;Server
+0-> "INV-001";"twelve euros";~;~:
+`)
+
+	var decoded support.Invoice
+	err := csvt.Unmarshal(data, &decoded)
+	invalid := csvt.IsInvalidValue(err)
+	if invalid == nil || invalid.Path != "Invoice[0].Total" {
+		t.Fatalf("expected InvalidValue at 'Invoice[0].Total' from the custom unmarshaler, got: %v", err)
+	}
+}
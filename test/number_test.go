@@ -0,0 +1,65 @@
+package test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestNumbers_RoundTrip(t *testing.T) {
+	measures := []support.Measure{
+		{Name: "exponents", Single: 1e6, Double: 1e-7, Signed: math.MinInt64, Small: math.MinInt8, Counter: math.MaxUint64, Sample: 3.0},
+		{Name: "limits", Single: math.MaxFloat32, Double: math.SmallestNonzeroFloat64, Signed: math.MaxInt64, Small: math.MaxInt8, Counter: 0, Sample: uint64(math.MaxUint64)},
+		{Name: "decimals", Single: 0.1, Double: 0.1 + 0.2, Sample: 7},
+		{Name: "specials", Single: float32(math.Inf(-1)), Double: math.Inf(1), Sample: math.NaN()},
+	}
+
+	result, err := csvt.Marshal(measures[0], measures[1], measures[2], measures[3])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded []support.Measure
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, result)
+	}
+
+	for i, exp := range measures[:3] {
+		if decoded[i] != exp {
+			t.Errorf("expected %+v, got %+v", exp, decoded[i])
+		}
+	}
+
+	specials := decoded[3]
+	if !math.IsInf(float64(specials.Single), -1) || !math.IsInf(specials.Double, 1) {
+		t.Errorf("expected infinities, got %+v", specials)
+	}
+	if sample, ok := specials.Sample.(float64); !ok || !math.IsNaN(sample) {
+		t.Errorf("expected NaN sample, got %#v", specials.Sample)
+	}
+}
+
+func TestNumbers_Overflow(t *testing.T) {
+	result, err := csvt.Marshal(support.Measure{Name: "overflow", Small: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	broken := strings.Replace(string(result), `"overflow";0.0;0.0;0;100;`, `"overflow";0.0;0.0;0;300;`, 1)
+
+	var decoded support.Measure
+	err = csvt.Unmarshal([]byte(broken), &decoded)
+	invalid := csvt.IsInvalidValue(err)
+	if invalid == nil || invalid.Path != "Measure[0].Small" {
+		t.Errorf("expected overflow error for 'Measure[0].Small', got: %v\n%s", err, broken)
+	}
+
+	broken = strings.Replace(string(result), `;100;`, `;1.5;`, 1)
+	err = csvt.Unmarshal([]byte(broken), &decoded)
+	if mismatch := csvt.IsTypeMismatch(err); mismatch == nil || mismatch.Path != "Measure[0].Small" {
+		t.Errorf("expected TypeMismatch for 'Measure[0].Small', got: %v", err)
+	}
+}
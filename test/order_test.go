@@ -0,0 +1,132 @@
+package test
+
+import (
+	"bytes"
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func orderedPackage() support.Package {
+	return support.Package{
+		Name:     "csvt",
+		Version:  "1.0.0",
+		Keywords: []string{"csv", "tables"},
+		Lang: &support.Lang{
+			Name:    "Go",
+			Release: support.Release{Version: "1.25.3", Stable: true},
+			Tags:    []string{"go"},
+			Attributes: map[string]string{
+				"procedural": "true",
+				"oop":        "some",
+				"functional": "false",
+				"generic":    "true",
+			},
+		},
+	}
+}
+
+func tableNames(t *testing.T, output []byte) []string {
+	t.Helper()
+
+	re := regexp.MustCompile(`(?m)^(?:/\*\*|///) ([^&\n]+)`)
+	names := []string{}
+	for _, match := range re.FindAllSubmatch(output, -1) {
+		names = append(names, string(match[1]))
+	}
+	return names
+}
+
+func TestMarshal_StableOutput(t *testing.T) {
+	first, err := csvt.Marshal(orderedPackage())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 20 {
+		result, err := csvt.Marshal(orderedPackage())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(first, result) {
+			t.Fatalf("expected stable output, got:\n%s\nand:\n%s", first, result)
+		}
+	}
+
+	expMap := `"functional"="false","generic"="true","oop"="some","procedural"="true"^`
+	if !strings.Contains(string(first), expMap) {
+		t.Errorf("expected sorted map keys, got: %s", first)
+	}
+
+	expTables := []string{"Package", "common-array", "Lang", "Release", "common-map"}
+	if names := tableNames(t, first); !slices.Equal(names, expTables) {
+		t.Errorf("expected tables %v, got %v", expTables, names)
+	}
+}
+
+func TestMarshal_OrderOptions(t *testing.T) {
+	opts := csvt.MarshalOptions{
+		Compact:    true,
+		TableOrder: csvt.TABLE_ORDER_ALPHABETICAL,
+		MapKeyOrder: func(a, b any) int {
+			return cmp.Compare(b.(string), a.(string))
+		},
+	}
+
+	result, err := csvt.MarshalOpts(opts, orderedPackage())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expMap := `"procedural"="true","oop"="some","generic"="true","functional"="false"^`
+	if !strings.Contains(string(result), expMap) {
+		t.Errorf("expected reversed map keys, got: %s", result)
+	}
+
+	expTables := []string{"Package", "Lang", "Release", "common-array", "common-map"}
+	if names := tableNames(t, result); !slices.Equal(names, expTables) {
+		t.Errorf("expected tables %v, got %v", expTables, names)
+	}
+
+	var decoded support.Package
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Lang.Attributes["generic"] != "true" {
+		t.Errorf("unexpected decoded attributes: %v", decoded.Lang.Attributes)
+	}
+}
+
+func TestMarshal_StablePointerKeys(t *testing.T) {
+	forest := func() support.Forest {
+		forest := support.Forest{
+			ByLeaf:   map[*support.Leaf]int{},
+			ByBranch: map[support.Branch]string{},
+		}
+		for i := range 4 {
+			forest.ByLeaf[&support.Leaf{Name: "same"}] = i
+			forest.ByBranch[support.Branch{Leaf: &support.Leaf{Name: "same"}}] = strings.Repeat("b", i)
+		}
+		return forest
+	}
+
+	first, err := csvt.Marshal(forest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 50 {
+		result, err := csvt.Marshal(forest())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(first, result) {
+			t.Fatalf("expected stable output, got:\n%s\nand:\n%s", first, result)
+		}
+	}
+}
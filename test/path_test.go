@@ -0,0 +1,69 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestUnmarshal_ErrorPath(t *testing.T) {
+	data := string(support.LoadFile(t, "../support/lang_table.csvt"))
+
+	cases := []struct {
+		name    string
+		old     string
+		new     string
+		expPath string
+	}{
+		{"field", `"0.16.0-dev.747+493ad58ff";false:`, `16;false:`, "Lang[1].Release.Version"},
+		{"array", `"zig","ziglang"|`, `"zig",5|`, "Lang[1].Tags[1]"},
+		{"map", `"oop"="false"`, `"oop"=false`, `Lang[1].Attributes["oop"]`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			broken := strings.Replace(data, c.old, c.new, 1)
+
+			var langs []support.Lang
+			err := csvt.Unmarshal([]byte(broken), &langs)
+
+			mismatch := csvt.IsTypeMismatch(err)
+			if mismatch == nil {
+				t.Fatalf("expected TypeMismatch error, got: %v", err)
+			}
+			if mismatch.Path != c.expPath {
+				t.Errorf("expected path '%s', got '%s'", c.expPath, mismatch.Path)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_MissingReferencePath(t *testing.T) {
+	data := string(support.LoadFile(t, "../support/lang_table.csvt"))
+	broken := strings.Replace(data, "$common-array_2", "$common-array_9", 1)
+
+	var langs []support.Lang
+	err := csvt.Unmarshal([]byte(broken), &langs)
+
+	missing := csvt.IsMissingReference(err)
+	if missing == nil {
+		t.Fatalf("expected MissingReference error, got: %v", err)
+	}
+	if missing.Path != "Lang[1].Tags" || missing.Reference != "common-array_9" {
+		t.Errorf("unexpected missing reference '%s' at '%s'", missing.Reference, missing.Path)
+	}
+}
+
+func TestMarshal_ErrorPath(t *testing.T) {
+	_, err := csvt.Marshal(support.Session{})
+
+	unsupported := csvt.IsUnsupportedType(err)
+	if unsupported == nil {
+		t.Fatalf("expected UnsupportedType error, got: %v", err)
+	}
+	if unsupported.Path != "Session[0].Credentials" {
+		t.Errorf("expected path 'Session[0].Credentials', got '%s'", unsupported.Path)
+	}
+}
@@ -0,0 +1,142 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestMarshal_PointerFields(t *testing.T) {
+	project := support.Project{
+		Name: "csvt",
+		Lang: &support.Lang{
+			Name: "Go",
+			Tags: []string{"go"},
+		},
+		Latest: &support.Release{
+			Version: "0.2.4",
+			Stable:  true,
+		},
+	}
+
+	result, err := csvt.Marshal(&project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "/** Project&") {
+		t.Errorf("expected Project root table, not found: %s", output)
+	}
+	if !strings.Contains(output, "$Lang&") || !strings.Contains(output, "$Release&") {
+		t.Errorf("expected pointer fields as references, got: %s", output)
+	}
+	if !strings.Contains(output, ";~:") {
+		t.Errorf("expected nil pointer marker, got: %s", output)
+	}
+
+	var decoded []*support.Project
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(decoded))
+	}
+
+	item := decoded[0]
+	if item.Lang == nil || item.Lang.Name != "Go" {
+		t.Errorf("unexpected Lang: %+v", item.Lang)
+	}
+	if item.Latest == nil || item.Latest.Version != "0.2.4" || !item.Latest.Stable {
+		t.Errorf("unexpected Latest: %+v", item.Latest)
+	}
+	if item.Previous != nil {
+		t.Errorf("expected nil Previous, got %+v", item.Previous)
+	}
+}
+
+func TestMarshal_NilRoot(t *testing.T) {
+	var project *support.Project
+	_, err := csvt.Marshal(project)
+	if err == nil {
+		t.Fatalf("expected error for nil root, but got nil")
+	}
+}
+
+func TestMarshal_SharedPointer(t *testing.T) {
+	release := &support.Release{
+		Version: "1.25.3",
+		Stable:  true,
+	}
+
+	project := support.Project{
+		Name:     "csvt",
+		Latest:   release,
+		Previous: release,
+	}
+
+	result, err := csvt.Marshal(project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if count := strings.Count(output, "\"1.25.3\""); count != 1 {
+		t.Errorf("expected a single Release row, found %d: %s", count, output)
+	}
+
+	var decoded support.Project
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Latest == nil || decoded.Latest != decoded.Previous {
+		t.Errorf("expected Latest and Previous to share the same pointer")
+	}
+}
+
+func TestMarshal_CyclicGraph(t *testing.T) {
+	root := &support.Node{Name: "root"}
+	left := &support.Node{Name: "left", Parent: root}
+	right := &support.Node{Name: "right", Parent: root}
+	root.Children = []*support.Node{left, right}
+
+	result, err := csvt.Marshal(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded []*support.Node
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(decoded) != 3 {
+		t.Fatalf("expected 3 rows, got %d: %s", len(decoded), result)
+	}
+
+	tree := decoded[0]
+	if tree.Name != "root" || tree.Parent != nil {
+		t.Fatalf("unexpected root node: %+v", tree)
+	}
+	if len(tree.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(tree.Children))
+	}
+
+	for i, child := range tree.Children {
+		if child.Parent != tree {
+			t.Errorf("expected child %d parent to be the root pointer", i)
+		}
+		if child != decoded[i+1] {
+			t.Errorf("expected child %d to be the same pointer as row %d", i, i+1)
+		}
+	}
+}
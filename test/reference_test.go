@@ -0,0 +1,80 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestReference_TableNames(t *testing.T) {
+	shelf := support.Shelf{
+		Owner:   support.User_Profile{Display_Name: "rafael"},
+		Counter: support.Box[int]{Value: 3},
+		Label:   support.Box[string]{Value: "books"},
+		Entries: []support.Pair[string, support.User_Profile]{
+			{Key: "first", Value: support.User_Profile{Display_Name: "ada"}},
+			{Key: "second", Value: support.User_Profile{Display_Name: "alan"}},
+		},
+	}
+	shelf.Origin.X, shelf.Origin.Y = 1, 2
+	shelf.Size.Width, shelf.Size.Height = 30, 40
+
+	result, err := csvt.Marshal(shelf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+	for _, forbidden := range []string{"[", "]", "struct {"} {
+		if strings.Contains(output, forbidden) {
+			t.Errorf("expected table names without %q, got:\n%s", forbidden, output)
+		}
+	}
+
+	if count := strings.Count(output, "/// Box&"); count != 2 {
+		t.Errorf("expected a table per Box instantiation, got %d:\n%s", count, output)
+	}
+	if count := strings.Count(output, "/// struct&"); count != 2 {
+		t.Errorf("expected a table per anonymous struct, got %d:\n%s", count, output)
+	}
+
+	if report := csvt.Validate(result); !report.Valid() {
+		t.Errorf("expected a valid document, got: %v", report.Issues)
+	}
+
+	var decoded support.Shelf
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+
+	if !reflect.DeepEqual(decoded, shelf) {
+		t.Errorf("expected %+v, got %+v", shelf, decoded)
+	}
+}
+
+func TestReference_EqualRowsAcrossTables(t *testing.T) {
+	crate := support.Crate{A: support.Box[int]{Value: 1}}
+	crate.D.X = 1
+
+	result, err := csvt.Marshal(crate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+	if !strings.Contains(output, "$Box&") || !strings.Contains(output, "$struct&") {
+		t.Errorf("expected a reference into each table, got:\n%s", output)
+	}
+
+	var decoded support.Crate
+	if err := csvt.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+
+	if decoded != crate {
+		t.Errorf("expected %+v, got %+v", crate, decoded)
+	}
+}
@@ -0,0 +1,70 @@
+package support
+
+type Account struct {
+	Name     string
+	password string
+}
+
+func NewAccount(name, password string) Account {
+	return Account{
+		Name:     name,
+		password: password,
+	}
+}
+
+func (a Account) Password() string {
+	return a.password
+}
+
+type Credentials struct {
+	token string
+}
+
+type Session struct {
+	Account     Account
+	Credentials Credentials
+}
+
+type AllSkipped struct {
+	Draft string `csv:"-"`
+	Notes string `csv:"-"`
+}
+
+type HasSkipped struct {
+	Name string
+	S    AllSkipped
+}
+
+type Token struct {
+	secret string
+}
+
+func NewToken(secret string) Token {
+	return Token{
+		secret: secret,
+	}
+}
+
+func (t Token) MarshalCSVT() (string, error) {
+	return t.secret, nil
+}
+
+func (t *Token) UnmarshalCSVT(text string) error {
+	t.secret = text
+	return nil
+}
+
+type Vault struct {
+	Name  string
+	Token Token
+}
+
+type Channel struct {
+	Name   string
+	Events chan string
+}
+
+type Relay struct {
+	Name   string
+	Target *Channel
+}
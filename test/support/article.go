@@ -0,0 +1,21 @@
+package support
+
+type Audit struct {
+	CreatedBy string
+	UpdatedBy string
+}
+
+type Article struct {
+	Audit
+	Title string
+}
+
+type Draft struct {
+	*Audit
+	Title string
+}
+
+type Archive struct {
+	Audit `csv:",table"`
+	Title string
+}
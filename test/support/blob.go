@@ -0,0 +1,16 @@
+package support
+
+type Blob struct {
+	Name   string
+	Data   []byte
+	Digest [4]byte
+}
+
+type Library struct {
+	Blobs []Blob
+}
+
+type Queue struct {
+	List  []Priority
+	Fixed [2]Priority
+}
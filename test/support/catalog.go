@@ -0,0 +1,31 @@
+package support
+
+import "net/netip"
+
+type Coord struct {
+	X int
+	Y int
+}
+
+type Catalog struct {
+	ByCode    map[int]string
+	ByCounter map[uint64]bool
+	ByFlag    map[bool]int
+	ByRatio   map[float64]string
+	ByAddress map[netip.Addr]string
+	ByCoord   map[Coord]string
+	ByLevel   map[Level]string
+}
+
+type Leaf struct {
+	Name string
+}
+
+type Branch struct {
+	Leaf *Leaf
+}
+
+type Forest struct {
+	ByLeaf   map[*Leaf]int
+	ByBranch map[Branch]string
+}
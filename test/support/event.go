@@ -0,0 +1,10 @@
+package support
+
+import "time"
+
+type Event struct {
+	Name    string
+	At      time.Time
+	Ends    *time.Time
+	Timeout time.Duration
+}
@@ -0,0 +1,28 @@
+package support
+
+type User_Profile struct {
+	Display_Name string
+}
+
+type Box[T any] struct {
+	Value T
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type Shelf struct {
+	Owner   User_Profile
+	Counter Box[int]
+	Label   Box[string]
+	Entries []Pair[string, User_Profile]
+	Origin  struct{ X, Y int }
+	Size    struct{ Width, Height int }
+}
+
+type Crate struct {
+	A Box[int]
+	D struct{ X int }
+}
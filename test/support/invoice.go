@@ -0,0 +1,27 @@
+package support
+
+import (
+	"fmt"
+	"net"
+)
+
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalCSVT() (string, error) {
+	return fmt.Sprintf("%d %s", m.Cents, m.Currency), nil
+}
+
+func (m *Money) UnmarshalCSVT(text string) error {
+	_, err := fmt.Sscanf(text, "%d %s", &m.Cents, &m.Currency)
+	return err
+}
+
+type Invoice struct {
+	Id       string
+	Total    Money
+	Discount *Money
+	Server   net.IP
+}
@@ -0,0 +1,50 @@
+package support
+
+type Status string
+
+const (
+	Open   Status = "open"
+	Closed Status = "closed"
+)
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+)
+
+func (l Level) String() string {
+	return [...]string{"DEBUG", "INFO", "WARN"}[l]
+}
+
+type Priority uint8
+
+type Severity int
+
+const (
+	Minor Severity = iota + 1
+	Major
+	Critical
+)
+
+type Log struct {
+	Message  string
+	Status   Status
+	Level    Level
+	Priority Priority
+	Severity Severity
+}
+
+type Stage string
+
+const (
+	Planned Stage = "planned"
+	Review  Stage = "review"
+)
+
+type Ticket struct {
+	Title string
+	Stage Stage
+}
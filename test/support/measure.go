@@ -0,0 +1,11 @@
+package support
+
+type Measure struct {
+	Name    string
+	Single  float32
+	Double  float64
+	Signed  int64
+	Small   int8
+	Counter uint64
+	Sample  any
+}
@@ -0,0 +1,7 @@
+package support
+
+type Node struct {
+	Name     string
+	Parent   *Node
+	Children []*Node
+}
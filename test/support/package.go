@@ -0,0 +1,9 @@
+package support
+
+type Package struct {
+	Name     string   `csv:"name"`
+	Version  string   `csv:"version,alias=release|tag"`
+	Checksum string   `csv:"-"`
+	Keywords []string `csv:"keywords,omitempty"`
+	Lang     *Lang    `csv:"lang,omitempty"`
+}
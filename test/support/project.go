@@ -0,0 +1,8 @@
+package support
+
+type Project struct {
+	Name     string
+	Lang     *Lang
+	Latest   *Release
+	Previous *Release
+}
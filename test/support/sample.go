@@ -0,0 +1,8 @@
+package support
+
+type Sample struct {
+	Id     [4]byte
+	Point  [3]float64
+	Labels [2]string
+	Grid   [2][2]int
+}
@@ -0,0 +1,40 @@
+package support
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+func (c Circle) Area() float64 {
+	return 3.14 * c.Radius * c.Radius
+}
+
+type Square struct {
+	Side float64
+}
+
+func (s *Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+type Hexagon struct {
+	Side float64
+}
+
+func (h Hexagon) Area() float64 {
+	return 2.6 * h.Side * h.Side
+}
+
+type Drawing struct {
+	Shapes []Shape
+}
+
+type Canvas struct {
+	Name       string
+	Background Shape
+	Shapes     []Shape
+	Meta       any
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestSyntaxError_UnterminatedString(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	broken := strings.Replace(string(data), `1-> "Zig";`, `1-> "Zig;`, 1)
+
+	var langs []support.Lang
+	err := csvt.Unmarshal([]byte(broken), &langs)
+
+	syntax := csvt.IsSyntaxError(err)
+	if syntax == nil {
+		t.Fatalf("expected syntax error, got: %v", err)
+	}
+
+	if syntax.Table != "Lang&fc2d3b2a541ee9ef4fe80e26e672cfc9e569594f" {
+		t.Errorf("unexpected table '%s'", syntax.Table)
+	}
+	if syntax.Row != 1 || syntax.Line != 4 || syntax.Column != 5 {
+		t.Errorf("expected row 1 at 4:5, got row %d at %d:%d", syntax.Row, syntax.Line, syntax.Column)
+	}
+	if !strings.HasPrefix(syntax.Snippet, `1-> "Zig;`) {
+		t.Errorf("unexpected snippet '%s'", syntax.Snippet)
+	}
+}
+
+func TestSyntaxError_UnknownValue(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	broken := strings.Replace(string(data), `"1.25.3";true:`, `"1.25.3";yes:`, 1)
+
+	var langs []support.Lang
+	err := csvt.Unmarshal([]byte(broken), &langs)
+
+	syntax := csvt.IsSyntaxError(err)
+	if syntax == nil {
+		t.Fatalf("expected syntax error, got: %v", err)
+	}
+
+	if syntax.Row != 0 || syntax.Line != 8 || syntax.Column != 14 {
+		t.Errorf("expected row 0 at 8:14, got row %d at %d:%d", syntax.Row, syntax.Line, syntax.Column)
+	}
+}
+
+func TestSyntaxError_RowOutsideTable(t *testing.T) {
+	data := "\n0-> \"Go\":\n"
+
+	var lang support.Lang
+	err := csvt.Unmarshal([]byte(data), &lang)
+
+	syntax := csvt.IsSyntaxError(err)
+	if syntax == nil {
+		t.Fatalf("expected syntax error, got: %v", err)
+	}
+
+	if syntax.Row != -1 || syntax.Line != 2 || syntax.Column != 1 {
+		t.Errorf("expected line 2 column 1 outside of rows, got row %d at %d:%d", syntax.Row, syntax.Line, syntax.Column)
+	}
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestMarshal_TaggedStructure(t *testing.T) {
+	pkg := support.Package{
+		Name:     "go-csvt",
+		Version:  "v0.2.4",
+		Checksum: "9f86d081884c7d65",
+	}
+
+	result, err := csvt.Marshal(pkg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "H-> name;version;keywords;lang\n") {
+		t.Errorf("expected tagged headers, got: %s", output)
+	}
+	if strings.Contains(output, pkg.Checksum) {
+		t.Errorf("expected skipped field to be omitted, got: %s", output)
+	}
+	if !strings.Contains(output, "\"go-csvt\";\"v0.2.4\";~;~:") {
+		t.Errorf("expected empty fields as nil markers, got: %s", output)
+	}
+
+	var decoded support.Package
+	err = csvt.UnmarshalOpts(result, &decoded, csvt.UnmarshalOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Name != pkg.Name || decoded.Version != pkg.Version {
+		t.Errorf("unexpected decoded package: %+v", decoded)
+	}
+	if decoded.Checksum != "" {
+		t.Errorf("expected skipped field to stay empty, got '%s'", decoded.Checksum)
+	}
+}
+
+func TestUnmarshal_TagAliases(t *testing.T) {
+	data := support.LoadFile(t, "../support/package_legacy.csvt")
+
+	var result []support.Package
+	err := csvt.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expLen := 2
+	if len(result) != expLen {
+		t.Fatalf("expected %d items, got %d", expLen, len(result))
+	}
+
+	expVersion := "v0.6.0"
+	if result[1].Version != expVersion {
+		t.Errorf("expected Version '%s', got '%s'", expVersion, result[1].Version)
+	}
+
+	expKeywords := []string{"collections", "generics"}
+	if len(result[1].Keywords) != len(expKeywords) || result[1].Keywords[1] != expKeywords[1] {
+		t.Errorf("unexpected Keywords: %v", result[1].Keywords)
+	}
+	if result[0].Keywords != nil {
+		t.Errorf("expected nil Keywords, got %v", result[0].Keywords)
+	}
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestMarshal_TimeValues(t *testing.T) {
+	at := time.Date(2025, time.October, 16, 10, 30, 0, 123456789, time.UTC)
+	event := support.Event{
+		Name:    "release",
+		At:      at,
+		Timeout: 90 * time.Second,
+	}
+
+	result, err := csvt.Marshal(event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	expRow := "\"release\";\"2025-10-16T10:30:00.123456789Z\";~;90000000000:"
+	if !strings.Contains(output, expRow) {
+		t.Errorf("expected row '%s', got: %s", expRow, output)
+	}
+
+	var decoded support.Event
+	err = csvt.Unmarshal(result, &decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !decoded.At.Equal(at) {
+		t.Errorf("expected At %v, got %v", at, decoded.At)
+	}
+	if decoded.Ends != nil {
+		t.Errorf("expected nil Ends, got %v", decoded.Ends)
+	}
+	if decoded.Timeout != event.Timeout {
+		t.Errorf("expected Timeout %v, got %v", event.Timeout, decoded.Timeout)
+	}
+}
+
+func TestMarshal_TimeOptions(t *testing.T) {
+	ends := time.Date(2025, time.October, 17, 0, 0, 0, 0, time.UTC)
+	event := support.Event{
+		Name:    "release",
+		Ends:    &ends,
+		Timeout: 90 * time.Minute,
+	}
+
+	opts := csvt.MarshalOptions{
+		TimeLayout:     time.DateOnly,
+		DurationString: true,
+	}
+
+	result, err := csvt.MarshalOpts(opts, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, "\"2025-10-17\";\"1h30m0s\":") {
+		t.Errorf("expected custom time layout and duration string, got: %s", output)
+	}
+
+	var decoded support.Event
+	err = csvt.UnmarshalOpts(result, &decoded, csvt.UnmarshalOptions{TimeLayout: time.DateOnly})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.Ends == nil || !decoded.Ends.Equal(ends) {
+		t.Errorf("expected Ends %v, got %v", ends, decoded.Ends)
+	}
+	if decoded.Timeout != event.Timeout {
+		t.Errorf("expected Timeout %v, got %v", event.Timeout, decoded.Timeout)
+	}
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Rafael24595/go-csvt/csvt"
+	"github.com/Rafael24595/go-csvt/test/support"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	data := support.LoadFile(t, "../support/lang_table.csvt")
+
+	report := csvt.Validate(data)
+	if !report.Valid() {
+		t.Errorf("expected a valid document, got: %v", report.Issues)
+	}
+
+	lang := support.Lang{
+		Name:       "Go",
+		Release:    support.Release{Version: "1.25.3", Stable: true},
+		Tags:       []string{},
+		Attributes: map[string]string{"oop": "some"},
+	}
+
+	result, err := csvt.Marshal(lang, lang)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report = csvt.Validate(result)
+	if !report.Valid() {
+		t.Errorf("expected a valid marshaled document, got: %v", report.Issues)
+	}
+}
+
+func TestValidate_Issues(t *testing.T) {
+	data := string(support.LoadFile(t, "../support/lang_table.csvt"))
+
+	replacer := strings.NewReplacer(
+		"5a5fb74a27bca302170bf2d87c53fdf2dd358d03_1;", "5a5fb74a27bca302170bf2d87c53fdf2dd358d03_5;",
+		`"1.25.3";true:`, `"1.25.3":`,
+		`2-> "zig"`, `3-> "zig"`,
+		"$common-map_2", "$common-mop_2",
+	)
+
+	report := csvt.Validate([]byte(replacer.Replace(data)))
+
+	kinds := map[csvt.IssueKind]int{}
+	for _, issue := range report.Issues {
+		kinds[issue.Kind]++
+	}
+
+	expected := map[csvt.IssueKind]int{
+		csvt.ISSUE_INDEX:       1,
+		csvt.ISSUE_ARITY:       1,
+		csvt.ISSUE_PREFIX:      1,
+		csvt.ISSUE_TABLE:       1,
+		csvt.ISSUE_UNREACHABLE: 2,
+	}
+
+	for kind, count := range expected {
+		if kinds[kind] != count {
+			t.Errorf("expected %d %s issues, got %d: %v", count, kind, kinds[kind], report.Issues)
+		}
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Kind == csvt.ISSUE_INDEX && (issue.Row != 1 || issue.Line != 4) {
+			t.Errorf("expected the index issue at row 1, line 4, got: %v", issue)
+		}
+	}
+}
+
+func TestValidate_InvalidReferenceIndex(t *testing.T) {
+	data := string(support.LoadFile(t, "../support/lang_table.csvt"))
+	broken := strings.Replace(data, "$common-array_2", "$common-array_x", 1)
+
+	report := csvt.Validate([]byte(broken))
+	if len(report.Issues) != 1 || report.Issues[0].Kind != csvt.ISSUE_SYNTAX {
+		t.Fatalf("expected a single syntax issue, got: %v", report.Issues)
+	}
+
+	var langs []support.Lang
+	if err := csvt.Unmarshal([]byte(broken), &langs); csvt.IsSyntaxError(err) == nil {
+		t.Errorf("expected SyntaxError on decode, got: %v", err)
+	}
+}
+
+func TestValidate_RepeatedTables(t *testing.T) {
+	first, err := csvt.Marshal(support.Lang{Name: "Go", Tags: []string{"go"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := csvt.Marshal(support.Lang{Name: "Zig", Tags: []string{"zig"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := string(first) + "\n" + string(second)
+	report := csvt.Validate([]byte(data))
+
+	kinds := map[csvt.IssueKind]int{}
+	for _, issue := range report.Issues {
+		kinds[issue.Kind]++
+		if issue.Line == 0 {
+			t.Errorf("expected the repeated table line, got: %v", issue)
+		}
+	}
+
+	if kinds[csvt.ISSUE_ROOT] != 1 || kinds[csvt.ISSUE_TABLE] == 0 {
+		t.Errorf("expected repeated root and secondary tables, got: %v", report.Issues)
+	}
+}